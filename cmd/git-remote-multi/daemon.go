@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/rpc"
+
+	cid "github.com/ipfs/go-cid"
+	blockstore "github.com/ipfs/go-ipfs-blockstore"
+	ipld "github.com/ipfs/go-ipld-format"
+	"github.com/libp2p/go-libp2p-core/host"
+	"github.com/libp2p/go-libp2p-core/peer"
+
+	"github.com/multiverse-vcs/go-multiverse/internal/p2p"
+	mcontext "github.com/multiverse-vcs/go-multiverse/pkg/command/context"
+	"github.com/multiverse-vcs/go-multiverse/pkg/object"
+	mrpc "github.com/multiverse-vcs/go-multiverse/pkg/rpc"
+	"github.com/multiverse-vcs/go-multiverse/pkg/rpc/repo"
+)
+
+// daemon contains the daemon calls made by the helper.
+type daemon interface {
+	// Search returns the repository at the remote path.
+	Search(remote string) (*object.Repository, error)
+	// Fetch adds the dags of the remote reachable from want but not from have to the blockstore.
+	Fetch(remote string, want, have []cid.Cid) error
+	// Push updates the remote branch to the commit with the given id.
+	Push(remote, branch string, id cid.Cid) error
+	// Tag updates the remote tag to the tag or commit with the given id.
+	Tag(remote, tag string, id cid.Cid, force bool) error
+	// Close releases all resources held by the daemon connection.
+	Close() error
+}
+
+// rpcDaemon calls the daemon over rpc and exchanges blocks with the sync protocol.
+type rpcDaemon struct {
+	ctx    context.Context
+	client *rpc.Client
+	host   host.Host
+	peer   peer.ID
+	blocks blockstore.Blockstore
+}
+
+// dialDaemon connects to the rpc server and the sync protocol of the daemon.
+//
+// The dag is served to the daemon so that it can fetch pushed objects.
+func dialDaemon(ctx context.Context, dag ipld.DAGService, blocks blockstore.Blockstore) (*rpcDaemon, error) {
+	client, err := mrpc.NewClient()
+	if err != nil {
+		return nil, errors.New(mrpc.DialErrMsg)
+	}
+
+	d := &rpcDaemon{
+		ctx:    ctx,
+		client: client,
+		blocks: blocks,
+	}
+
+	info, err := mcontext.Daemon()
+	if err != nil {
+		d.Close()
+		return nil, err
+	}
+
+	ph, err := p2p.NewClientHost(ctx)
+	if err != nil {
+		d.Close()
+		return nil, err
+	}
+
+	d.host = ph
+	d.peer = info.ID
+
+	p2p.ServeSync(ph, dag)
+	if err := ph.Connect(ctx, info); err != nil {
+		d.Close()
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// Search returns the repository at the remote path.
+func (d *rpcDaemon) Search(remote string) (*object.Repository, error) {
+	args := repo.SearchArgs{
+		Remote: remote,
+	}
+
+	var reply repo.SearchReply
+	if err := d.client.Call("Repo.Search", &args, &reply); err != nil {
+		return nil, err
+	}
+
+	return reply.Repository, nil
+}
+
+// Fetch adds the dags of the remote reachable from want but not from have to the blockstore.
+//
+// The daemon fetches the dags from the author first because it only serves local blocks.
+func (d *rpcDaemon) Fetch(remote string, want, have []cid.Cid) error {
+	args := repo.FetchArgs{
+		Remote: remote,
+		Want:   want,
+	}
+
+	if err := d.client.Call("Repo.Fetch", &args, nil); err != nil {
+		return err
+	}
+
+	return p2p.Fetch(d.ctx, d.host, d.peer, d.blocks, want, have)
+}
+
+// Push updates the remote branch to the commit with the given id.
+func (d *rpcDaemon) Push(remote, branch string, id cid.Cid) error {
+	args := repo.PushArgs{
+		Remote: remote,
+		Branch: branch,
+		Head:   id,
+		PeerID: d.host.ID(),
+	}
+
+	return d.client.Call("Repo.Push", &args, nil)
+}
+
+// Tag updates the remote tag to the tag or commit with the given id.
+func (d *rpcDaemon) Tag(remote, tag string, id cid.Cid, force bool) error {
+	args := repo.TagArgs{
+		Remote: remote,
+		Tag:    tag,
+		Force:  force,
+		ID:     id,
+		PeerID: d.host.ID(),
+	}
+
+	return d.client.Call("Repo.Tag", &args, nil)
+}
+
+// Close releases all resources held by the daemon connection.
+func (d *rpcDaemon) Close() error {
+	if d.host != nil {
+		d.host.Close()
+	}
+
+	return d.client.Close()
+}
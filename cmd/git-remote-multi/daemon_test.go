@@ -0,0 +1,111 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net"
+	"net/rpc"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	blockservice "github.com/ipfs/go-blockservice"
+	datastore "github.com/ipfs/go-datastore"
+	dssync "github.com/ipfs/go-datastore/sync"
+	blockstore "github.com/ipfs/go-ipfs-blockstore"
+	offline "github.com/ipfs/go-ipfs-exchange-offline"
+	merkledag "github.com/ipfs/go-merkledag"
+
+	"github.com/multiverse-vcs/go-multiverse/internal/remotetest"
+	"github.com/multiverse-vcs/go-multiverse/pkg/rpc/repo"
+)
+
+func TestCloneRemote(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	dir, err := ioutil.TempDir("", "git-*")
+	if err != nil {
+		t.Fatal("failed to create temp dir")
+	}
+	defer os.RemoveAll(dir)
+
+	mn, servers, err := remotetest.NewNetwork(ctx, dir, 2)
+	if err != nil {
+		t.Fatalf("failed to create network %v", err)
+	}
+
+	// the repository belongs to a peer other than the daemon
+	local, owner := servers[0], servers[1]
+
+	mrepo := addTestRepository(t, ctx, owner.Peer.Local)
+	if err := remotetest.AddRepository(ctx, owner, "test", mrepo); err != nil {
+		t.Fatalf("failed to add repository %v", err)
+	}
+
+	client, err := mn.GenPeer()
+	if err != nil {
+		t.Fatal("failed to create client host")
+	}
+
+	if err := mn.LinkAll(); err != nil {
+		t.Fatal("failed to link peers")
+	}
+
+	if _, err := mn.ConnectPeers(client.ID(), local.Peer.Host.ID()); err != nil {
+		t.Fatal("failed to connect to daemon")
+	}
+
+	server := rpc.NewServer()
+	if err := server.RegisterName("Repo", &repo.Service{Server: local}); err != nil {
+		t.Fatal("failed to register service")
+	}
+
+	serverConn, clientConn := net.Pipe()
+	go server.ServeConn(serverConn)
+
+	bstore := blockstore.NewBlockstore(dssync.MutexWrap(datastore.NewMapDatastore()))
+	dag := merkledag.NewDAGService(blockservice.New(bstore, offline.Exchange(bstore)))
+
+	d := &rpcDaemon{
+		ctx:    ctx,
+		client: rpc.NewClient(clientConn),
+		host:   client,
+		peer:   local.Peer.Host.ID(),
+		blocks: bstore,
+	}
+	defer d.Close()
+
+	gitRepo, err := git.PlainInit(filepath.Join(dir, "clone"), true)
+	if err != nil {
+		t.Fatal("failed to init git repo")
+	}
+
+	h := &helper{
+		ctx:    ctx,
+		remote: path.Join(owner.Peer.Host.ID().Pretty(), "test"),
+		daemon: d,
+		blocks: bstore,
+		dag:    dag,
+		repo:   gitRepo,
+	}
+
+	var buf bytes.Buffer
+	if err := h.List(&buf); err != nil {
+		t.Fatalf("failed to list %v", err)
+	}
+
+	fields := strings.Fields(buf.String())
+	if len(fields) < 2 || fields[1] != "refs/heads/default" {
+		t.Fatalf("unexpected list output %q", buf.String())
+	}
+
+	if _, err := gitRepo.CommitObject(plumbing.NewHash(fields[0])); err != nil {
+		t.Error("expected commit to be exported")
+	}
+}
@@ -0,0 +1,270 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	blockservice "github.com/ipfs/go-blockservice"
+	cid "github.com/ipfs/go-cid"
+	badger "github.com/ipfs/go-ds-badger2"
+	blockstore "github.com/ipfs/go-ipfs-blockstore"
+	offline "github.com/ipfs/go-ipfs-exchange-offline"
+	ipld "github.com/ipfs/go-ipld-format"
+	merkledag "github.com/ipfs/go-merkledag"
+
+	mgit "github.com/multiverse-vcs/go-multiverse/internal/git"
+	"github.com/multiverse-vcs/go-multiverse/pkg/object"
+)
+
+// DotDir is the name of the block cache directory inside the git dir.
+const DotDir = "multi"
+
+// helper translates git remote helper commands into daemon calls.
+type helper struct {
+	ctx     context.Context
+	remote  string
+	daemon  daemon
+	dstore  *badger.Datastore
+	blocks  blockstore.Blockstore
+	dag     ipld.DAGService
+	repo    *git.Repository
+	mrepo   *object.Repository
+	commits map[plumbing.Hash]cid.Cid
+}
+
+// newHelper returns a helper for the repository at the remote path.
+//
+// Blocks fetched from the daemon are cached in the git dir so that
+// later fetches only transfer new objects.
+func newHelper(ctx context.Context, remote string) (*helper, error) {
+	gitDir := os.Getenv("GIT_DIR")
+	if gitDir == "" {
+		return nil, errors.New("GIT_DIR is not set")
+	}
+
+	repo, err := git.PlainOpen(gitDir)
+	if err != nil {
+		return nil, err
+	}
+
+	dopts := badger.DefaultOptions
+	dstore, err := badger.NewDatastore(filepath.Join(gitDir, DotDir), &dopts)
+	if err != nil {
+		return nil, err
+	}
+
+	bstore := blockstore.NewBlockstore(dstore)
+	bserv := blockservice.New(bstore, offline.Exchange(bstore))
+
+	dag := merkledag.NewDAGService(bserv)
+
+	d, err := dialDaemon(ctx, dag, bstore)
+	if err != nil {
+		dstore.Close()
+		return nil, err
+	}
+
+	return &helper{
+		ctx:    ctx,
+		remote: remote,
+		daemon: d,
+		dstore: dstore,
+		blocks: bstore,
+		dag:    dag,
+		repo:   repo,
+	}, nil
+}
+
+// Close releases all resources held by the helper.
+func (h *helper) Close() error {
+	h.daemon.Close()
+	return h.dstore.Close()
+}
+
+// List writes the remote refs after adding their objects to the git repo.
+func (h *helper) List(w io.Writer) error {
+	mrepo, err := h.daemon.Search(h.remote)
+	if err != nil {
+		return err
+	}
+
+	h.mrepo = mrepo
+	if err := h.fetch(); err != nil {
+		return err
+	}
+
+	exporter := mgit.NewExporter(h.ctx, h.dag, h.repo)
+	for _, name := range sortedKeys(h.mrepo.Branches) {
+		hash, err := exporter.AddCommit(h.mrepo.Branches[name])
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(w, "%s %s\n", hash, plumbing.NewBranchReferenceName(name))
+	}
+
+	for _, name := range sortedKeys(h.mrepo.Tags) {
+		hash, err := exporter.AddTag(name, h.mrepo.Tags[name])
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(w, "%s %s\n", hash, plumbing.NewTagReferenceName(name))
+	}
+
+	// repositories created in multiverse may not have a default branch
+	head := h.mrepo.DefaultBranch
+	if _, ok := h.mrepo.Branches[head]; !ok && len(h.mrepo.Branches) > 0 {
+		head = sortedKeys(h.mrepo.Branches)[0]
+	}
+
+	if _, ok := h.mrepo.Branches[head]; ok {
+		fmt.Fprintf(w, "@%s HEAD\n", plumbing.NewBranchReferenceName(head))
+	}
+
+	// commits created in multiverse have no git hash so pushes must reuse the exported hashes
+	h.commits = exporter.Commits()
+
+	_, err = fmt.Fprintln(w)
+	return err
+}
+
+// fetch adds the dags of all remote refs that are not already cached.
+func (h *helper) fetch() error {
+	var want, have []cid.Cid
+	for _, refs := range []map[string]cid.Cid{h.mrepo.Branches, h.mrepo.Tags} {
+		for _, id := range refs {
+			has, err := h.blocks.Has(id)
+			if err != nil {
+				return err
+			}
+
+			if has {
+				have = append(have, id)
+			} else {
+				want = append(want, id)
+			}
+		}
+	}
+
+	if len(want) == 0 {
+		return nil
+	}
+
+	return h.daemon.Fetch(h.remote, want, have)
+}
+
+// Push updates the remote refs with the local refs in each refspec.
+func (h *helper) Push(w io.Writer, specs []string) error {
+	if h.mrepo == nil {
+		return errors.New("push requires refs to be listed")
+	}
+
+	importer := mgit.NewImporter(h.ctx, h.dag, h.repo, path.Base(h.remote))
+	if err := importer.LoadRepository(h.mrepo); err != nil {
+		return err
+	}
+
+	for hash, id := range h.commits {
+		importer.SetObject(hash, id)
+	}
+
+	for _, spec := range specs {
+		force := strings.HasPrefix(spec, "+")
+		parts := strings.SplitN(strings.TrimPrefix(spec, "+"), ":", 2)
+		if len(parts) != 2 {
+			return fmt.Errorf("invalid refspec %s", spec)
+		}
+
+		src, dst := parts[0], plumbing.ReferenceName(parts[1])
+
+		err := h.pushRef(importer, src, dst, force)
+		if err != nil {
+			fmt.Fprintf(w, "error %s %s\n", dst, err)
+		} else {
+			fmt.Fprintf(w, "ok %s\n", dst)
+		}
+	}
+
+	_, err := fmt.Fprintln(w)
+	return err
+}
+
+// pushRef imports the local ref src and updates the remote ref dst.
+func (h *helper) pushRef(importer *mgit.Importer, src string, dst plumbing.ReferenceName, force bool) error {
+	if src == "" {
+		return errors.New("deleting refs is not supported")
+	}
+
+	ref, err := h.repo.Reference(plumbing.ReferenceName(src), true)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case dst.IsBranch():
+		id, err := importer.AddCommit(ref.Hash())
+		if err != nil {
+			return err
+		}
+
+		return h.daemon.Push(h.remote, dst.Short(), id)
+	case dst.IsTag():
+		id, err := h.addTag(importer, ref.Hash())
+		if err != nil {
+			return err
+		}
+
+		return h.daemon.Tag(h.remote, dst.Short(), id, force)
+	default:
+		return errors.New("only branches and tags can be pushed")
+	}
+}
+
+// addTag returns the tag object for an annotated git tag or the commit for a lightweight tag.
+func (h *helper) addTag(importer *mgit.Importer, hash plumbing.Hash) (cid.Cid, error) {
+	gtag, err := h.repo.TagObject(hash)
+	if err == plumbing.ErrObjectNotFound {
+		return importer.AddCommit(hash)
+	}
+
+	if err != nil {
+		return cid.Cid{}, err
+	}
+
+	if gtag.TargetType != plumbing.CommitObject {
+		return cid.Cid{}, errors.New("tag must point to a commit")
+	}
+
+	id, err := importer.AddCommit(gtag.Target)
+	if err != nil {
+		return cid.Cid{}, err
+	}
+
+	tag := object.NewTag(id)
+	tag.Tagger = &object.Identity{Name: gtag.Tagger.Name, Email: gtag.Tagger.Email}
+	tag.Date = gtag.Tagger.When
+	tag.Message = gtag.Message
+
+	return object.AddTag(h.ctx, h.dag, tag)
+}
+
+// sortedKeys returns the keys of the map in sorted order.
+func sortedKeys(refs map[string]cid.Cid) []string {
+	var names []string
+	for name := range refs {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	return names
+}
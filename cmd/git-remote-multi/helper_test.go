@@ -0,0 +1,315 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	gobject "github.com/go-git/go-git/v5/plumbing/object"
+	blockservice "github.com/ipfs/go-blockservice"
+	cid "github.com/ipfs/go-cid"
+	datastore "github.com/ipfs/go-datastore"
+	dssync "github.com/ipfs/go-datastore/sync"
+	blockstore "github.com/ipfs/go-ipfs-blockstore"
+	offline "github.com/ipfs/go-ipfs-exchange-offline"
+	ipld "github.com/ipfs/go-ipld-format"
+	merkledag "github.com/ipfs/go-merkledag"
+	ufsio "github.com/ipfs/go-unixfs/io"
+
+	"github.com/multiverse-vcs/go-multiverse/pkg/merge"
+	"github.com/multiverse-vcs/go-multiverse/pkg/object"
+)
+
+// mockDaemon is a daemon that serves a single repository from a dag.
+type mockDaemon struct {
+	ctx  context.Context
+	dag  ipld.DAGService
+	repo *object.Repository
+}
+
+func (d *mockDaemon) Search(remote string) (*object.Repository, error) {
+	id, err := object.AddRepository(d.ctx, d.dag, d.repo)
+	if err != nil {
+		return nil, err
+	}
+
+	return object.GetRepository(d.ctx, d.dag, id)
+}
+
+func (d *mockDaemon) Fetch(remote string, want, have []cid.Cid) error {
+	return nil
+}
+
+func (d *mockDaemon) Push(remote, branch string, id cid.Cid) error {
+	prev := d.repo.Branches[branch]
+
+	base, err := merge.Base(d.ctx, d.dag, prev, id)
+	if err != nil {
+		return err
+	}
+
+	if base != prev {
+		return errors.New("branches are non-divergent")
+	}
+
+	d.repo.Branches[branch] = id
+	return nil
+}
+
+func (d *mockDaemon) Tag(remote, tag string, id cid.Cid, force bool) error {
+	if _, ok := d.repo.Tags[tag]; ok && !force {
+		return errors.New("tag already exists")
+	}
+
+	d.repo.Tags[tag] = id
+	return nil
+}
+
+func (d *mockDaemon) Close() error {
+	return nil
+}
+
+// newTestHelper returns a helper for a git repo in dir and a daemon
+// serving a repository that was created in multiverse.
+func newTestHelper(t *testing.T, dir string) (*helper, *mockDaemon) {
+	ctx := context.Background()
+
+	bstore := blockstore.NewBlockstore(dssync.MutexWrap(datastore.NewMapDatastore()))
+	dag := merkledag.NewDAGService(blockservice.New(bstore, offline.Exchange(bstore)))
+
+	repo, err := git.PlainInit(dir, true)
+	if err != nil {
+		t.Fatal("failed to init git repo")
+	}
+
+	d := &mockDaemon{
+		ctx:  ctx,
+		dag:  dag,
+		repo: addTestRepository(t, ctx, dag),
+	}
+
+	h := &helper{
+		ctx:    ctx,
+		remote: "peer/test",
+		daemon: d,
+		blocks: bstore,
+		dag:    dag,
+		repo:   repo,
+	}
+
+	return h, d
+}
+
+// addTestRepository adds a repository with a single commit to the dag.
+func addTestRepository(t *testing.T, ctx context.Context, dag ipld.DAGService) *object.Repository {
+	file := merkledag.NewRawNode([]byte("hello"))
+	if err := dag.Add(ctx, file); err != nil {
+		t.Fatal("failed to add file")
+	}
+
+	tree := ufsio.NewDirectory(dag)
+	if err := tree.AddChild(ctx, "README", file); err != nil {
+		t.Fatal("failed to add child")
+	}
+
+	node, err := tree.GetNode()
+	if err != nil {
+		t.Fatal("failed to get tree node")
+	}
+
+	if err := dag.Add(ctx, node); err != nil {
+		t.Fatal("failed to add tree")
+	}
+
+	commit := object.NewCommit()
+	commit.Tree = node.Cid()
+	commit.Message = "first"
+	commit.Date = time.Unix(1000, 0)
+	commit.Author = &object.Identity{Name: "test", Email: "test@example.com"}
+
+	commitID, err := object.AddCommit(ctx, dag, commit)
+	if err != nil {
+		t.Fatal("failed to add commit")
+	}
+
+	mrepo := object.NewRepository()
+	mrepo.DefaultBranch = "default"
+	mrepo.Branches["default"] = commitID
+
+	return mrepo
+}
+
+// commitOnto creates a git commit with the same tree as parent and updates the branch.
+func commitOnto(t *testing.T, repo *git.Repository, branch string, parent plumbing.Hash) plumbing.Hash {
+	prev, err := repo.CommitObject(parent)
+	if err != nil {
+		t.Fatal("failed to get parent commit")
+	}
+
+	sig := gobject.Signature{Name: "test", Email: "test@example.com", When: time.Unix(2000, 0)}
+	commit := gobject.Commit{
+		Author:       sig,
+		Committer:    sig,
+		Message:      "second",
+		TreeHash:     prev.TreeHash,
+		ParentHashes: []plumbing.Hash{parent},
+	}
+
+	obj := repo.Storer.NewEncodedObject()
+	if err := commit.Encode(obj); err != nil {
+		t.Fatal("failed to encode commit")
+	}
+
+	hash, err := repo.Storer.SetEncodedObject(obj)
+	if err != nil {
+		t.Fatal("failed to add commit")
+	}
+
+	ref := plumbing.NewHashReference(plumbing.NewBranchReferenceName(branch), hash)
+	if err := repo.Storer.SetReference(ref); err != nil {
+		t.Fatal("failed to set reference")
+	}
+
+	return hash
+}
+
+func TestList(t *testing.T) {
+	dir, err := ioutil.TempDir("", "git-*")
+	if err != nil {
+		t.Fatal("failed to create temp dir")
+	}
+	defer os.RemoveAll(dir)
+
+	h, _ := newTestHelper(t, dir)
+
+	var buf bytes.Buffer
+	if err := h.List(&buf); err != nil {
+		t.Fatalf("failed to list %v", err)
+	}
+
+	lines := strings.Split(buf.String(), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("unexpected list output %q", buf.String())
+	}
+
+	fields := strings.Fields(lines[0])
+	if len(fields) != 2 || fields[1] != "refs/heads/default" {
+		t.Fatalf("unexpected branch line %q", lines[0])
+	}
+
+	if _, err := h.repo.CommitObject(plumbing.NewHash(fields[0])); err != nil {
+		t.Error("expected commit to be exported")
+	}
+
+	if lines[1] != "@refs/heads/default HEAD" {
+		t.Errorf("unexpected head line %q", lines[1])
+	}
+}
+
+func TestPushNative(t *testing.T) {
+	dir, err := ioutil.TempDir("", "git-*")
+	if err != nil {
+		t.Fatal("failed to create temp dir")
+	}
+	defer os.RemoveAll(dir)
+
+	h, d := newTestHelper(t, dir)
+	first := d.repo.Branches["default"]
+
+	var buf bytes.Buffer
+	if err := h.List(&buf); err != nil {
+		t.Fatalf("failed to list %v", err)
+	}
+
+	parent := plumbing.NewHash(strings.Fields(buf.String())[0])
+	commitOnto(t, h.repo, "default", parent)
+
+	buf.Reset()
+	if err := h.Push(&buf, []string{"refs/heads/default:refs/heads/default"}); err != nil {
+		t.Fatalf("failed to push %v", err)
+	}
+
+	if buf.String() != "ok refs/heads/default\n\n" {
+		t.Fatalf("unexpected push output %q", buf.String())
+	}
+
+	commit, err := object.GetCommit(h.ctx, h.dag, d.repo.Branches["default"])
+	if err != nil {
+		t.Fatal("failed to get commit")
+	}
+
+	if len(commit.Parents) != 1 || commit.Parents[0] != first {
+		t.Error("expected parent to reuse native commit")
+	}
+}
+
+func TestPushStatus(t *testing.T) {
+	dir, err := ioutil.TempDir("", "git-*")
+	if err != nil {
+		t.Fatal("failed to create temp dir")
+	}
+	defer os.RemoveAll(dir)
+
+	h, d := newTestHelper(t, dir)
+
+	var buf bytes.Buffer
+	if err := h.Push(&buf, nil); err == nil {
+		t.Fatal("expected push before list to fail")
+	}
+
+	if err := h.List(&buf); err != nil {
+		t.Fatalf("failed to list %v", err)
+	}
+
+	parent := plumbing.NewHash(strings.Fields(buf.String())[0])
+	hash := commitOnto(t, h.repo, "default", parent)
+
+	if err := h.repo.Storer.SetReference(plumbing.NewHashReference("refs/tags/v1", hash)); err != nil {
+		t.Fatal("failed to set tag")
+	}
+
+	specs := []string{
+		"refs/heads/default:refs/heads/default",
+		"refs/tags/v1:refs/tags/v1",
+		":refs/heads/old",
+		"refs/heads/missing:refs/heads/missing",
+		"refs/heads/default:refs/notes/default",
+	}
+
+	buf.Reset()
+	if err := h.Push(&buf, specs); err != nil {
+		t.Fatalf("failed to push %v", err)
+	}
+
+	lines := strings.Split(buf.String(), "\n")
+	expect := []string{
+		"ok refs/heads/default",
+		"ok refs/tags/v1",
+		"error refs/heads/old deleting refs is not supported",
+		"error refs/heads/missing",
+		"error refs/notes/default only branches and tags can be pushed",
+		"",
+		"",
+	}
+
+	if len(lines) != len(expect) {
+		t.Fatalf("unexpected push output %q", buf.String())
+	}
+
+	for i, line := range expect {
+		if !strings.HasPrefix(lines[i], line) {
+			t.Errorf("expected %q got %q", line, lines[i])
+		}
+	}
+
+	if d.repo.Tags["v1"] != d.repo.Branches["default"] {
+		t.Error("expected lightweight tag to reference pushed commit")
+	}
+}
@@ -0,0 +1,101 @@
+// Command git-remote-multi is a git remote helper for multiverse repositories.
+//
+// Git runs the helper for urls of the form multi://<peer-id>/<repo>.
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// URLPrefix is the url scheme handled by the helper.
+const URLPrefix = "multi://"
+
+func main() {
+	if len(os.Args) < 3 {
+		fmt.Fprintln(os.Stderr, "usage: git-remote-multi <remote> <url>")
+		os.Exit(1)
+	}
+
+	if err := run(os.Args[2]); err != nil {
+		fmt.Fprintf(os.Stderr, "git-remote-multi: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// run starts a helper for the url and serves commands from git.
+func run(url string) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if !strings.HasPrefix(url, URLPrefix) {
+		return errors.New("invalid multi url")
+	}
+
+	h, err := newHelper(ctx, strings.TrimPrefix(url, URLPrefix))
+	if err != nil {
+		return err
+	}
+	defer h.Close()
+
+	return serve(h, os.Stdin, os.Stdout)
+}
+
+// serve reads commands from in until the input is closed or a blank line is read.
+func serve(h *helper, in io.Reader, out io.Writer) error {
+	r := bufio.NewScanner(in)
+	w := bufio.NewWriter(out)
+
+	for r.Scan() {
+		line := r.Text()
+
+		switch {
+		case line == "":
+			return nil
+		case line == "capabilities":
+			fmt.Fprintln(w, "fetch")
+			fmt.Fprintln(w, "push")
+			fmt.Fprintln(w)
+		case line == "list" || line == "list for-push":
+			if err := h.List(w); err != nil {
+				return err
+			}
+		case strings.HasPrefix(line, "fetch "):
+			// objects are written to the git repo while listing
+			readBatch(r)
+			fmt.Fprintln(w)
+		case strings.HasPrefix(line, "push "):
+			specs := append([]string{line}, readBatch(r)...)
+			for i, spec := range specs {
+				specs[i] = strings.TrimPrefix(spec, "push ")
+			}
+
+			if err := h.Push(w, specs); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("unsupported command %s", line)
+		}
+
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	}
+
+	return r.Err()
+}
+
+// readBatch returns the remaining lines of a batch terminated by a blank line.
+func readBatch(r *bufio.Scanner) []string {
+	var lines []string
+	for r.Scan() && r.Text() != "" {
+		lines = append(lines, r.Text())
+	}
+
+	return lines
+}
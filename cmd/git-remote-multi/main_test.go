@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+func TestServe(t *testing.T) {
+	dir, err := ioutil.TempDir("", "git-*")
+	if err != nil {
+		t.Fatal("failed to create temp dir")
+	}
+	defer os.RemoveAll(dir)
+
+	h, _ := newTestHelper(t, dir)
+
+	// git clones by listing refs and fetching them in a batch
+	script := strings.Join([]string{
+		"capabilities",
+		"list",
+		"fetch " + plumbing.ZeroHash.String() + " refs/heads/default",
+		"",
+		"",
+	}, "\n")
+
+	var out bytes.Buffer
+	if err := serve(h, strings.NewReader(script), &out); err != nil {
+		t.Fatalf("failed to serve %v", err)
+	}
+
+	lines := strings.Split(out.String(), "\n")
+	if len(lines) != 8 {
+		t.Fatalf("unexpected output %q", out.String())
+	}
+
+	if strings.Join(lines[:3], "\n") != "fetch\npush\n" {
+		t.Errorf("unexpected capabilities %q", lines[:3])
+	}
+
+	fields := strings.Fields(lines[3])
+	if len(fields) != 2 || fields[1] != "refs/heads/default" {
+		t.Fatalf("unexpected branch line %q", lines[3])
+	}
+
+	if lines[4] != "@refs/heads/default HEAD" || lines[5] != "" || lines[6] != "" {
+		t.Errorf("unexpected list and fetch output %q", lines[4:])
+	}
+
+	commitOnto(t, h.repo, "default", plumbing.NewHash(fields[0]))
+
+	// git pushes by listing refs for push and sending a batch of refspecs
+	script = strings.Join([]string{
+		"list for-push",
+		"push refs/heads/default:refs/heads/default",
+		"push :refs/heads/old",
+		"",
+		"",
+	}, "\n")
+
+	out.Reset()
+	if err := serve(h, strings.NewReader(script), &out); err != nil {
+		t.Fatalf("failed to serve %v", err)
+	}
+
+	expect := "ok refs/heads/default\nerror refs/heads/old deleting refs is not supported\n\n"
+	if !strings.HasSuffix(out.String(), expect) {
+		t.Errorf("unexpected push output %q", out.String())
+	}
+
+	if err := serve(h, strings.NewReader("option verbosity 1\n"), &out); err == nil {
+		t.Error("expected unsupported command to fail")
+	}
+}
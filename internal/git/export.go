@@ -0,0 +1,404 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	cid "github.com/ipfs/go-cid"
+	ipld "github.com/ipfs/go-ipld-format"
+	merkledag "github.com/ipfs/go-merkledag"
+	ufs "github.com/ipfs/go-unixfs"
+	ufsio "github.com/ipfs/go-unixfs/io"
+
+	"github.com/multiverse-vcs/go-multiverse/pkg/dag"
+	mobject "github.com/multiverse-vcs/go-multiverse/pkg/object"
+)
+
+// Exporter adds objects from a dag to a git repo.
+type Exporter struct {
+	ctx     context.Context
+	dag     ipld.DAGService
+	repo    *git.Repository
+	objects map[string]plumbing.Hash
+	commits map[plumbing.Hash]cid.Cid
+}
+
+// ExportToFS is a helper to export a repository to a git repo in a directory.
+//
+// A bare git repo is created if the directory does not contain one.
+func ExportToFS(ctx context.Context, dag ipld.DAGService, mrepo *mobject.Repository, dir string) error {
+	repo, err := git.PlainOpen(dir)
+	if err == git.ErrRepositoryNotExists {
+		repo, err = git.PlainInit(dir, true)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return NewExporter(ctx, dag, repo).AddRepository(mrepo)
+}
+
+// NewExporter returns an exporter for the given repo.
+func NewExporter(ctx context.Context, dag ipld.DAGService, repo *git.Repository) *Exporter {
+	return &Exporter{
+		ctx:     ctx,
+		dag:     dag,
+		repo:    repo,
+		objects: make(map[string]plumbing.Hash),
+		commits: make(map[plumbing.Hash]cid.Cid),
+	}
+}
+
+// Commits returns the CIDs of all exported commits keyed by git hash.
+func (e *Exporter) Commits() map[plumbing.Hash]cid.Cid {
+	return e.commits
+}
+
+// AddRepository adds all branches and tags to the git repo.
+func (e *Exporter) AddRepository(mrepo *mobject.Repository) error {
+	for name, id := range mrepo.Branches {
+		hash, err := e.AddCommit(id)
+		if err != nil {
+			return err
+		}
+
+		ref := plumbing.NewHashReference(plumbing.NewBranchReferenceName(name), hash)
+		if err := e.repo.Storer.SetReference(ref); err != nil {
+			return err
+		}
+	}
+
+	for name, id := range mrepo.Tags {
+		hash, err := e.AddTag(name, id)
+		if err != nil {
+			return err
+		}
+
+		ref := plumbing.NewHashReference(plumbing.NewTagReferenceName(name), hash)
+		if err := e.repo.Storer.SetReference(ref); err != nil {
+			return err
+		}
+	}
+
+	branch := mrepo.DefaultBranch
+	if _, ok := mrepo.Branches[branch]; !ok {
+		branch = firstBranch(mrepo)
+	}
+
+	if branch == "" {
+		return nil
+	}
+
+	head := plumbing.NewSymbolicReference(plumbing.HEAD, plumbing.NewBranchReferenceName(branch))
+	return e.repo.Storer.SetReference(head)
+}
+
+// AddTag adds the tag with the given name and CID to the git repo.
+//
+// Imported tags reference commits directly and are exported as lightweight tags.
+func (e *Exporter) AddTag(name string, id cid.Cid) (plumbing.Hash, error) {
+	node, err := e.dag.Get(e.ctx, id)
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+
+	if _, err := mobject.CommitFromCBOR(node.RawData()); err == nil {
+		return e.AddCommit(id)
+	}
+
+	mtag, err := mobject.TagFromCBOR(node.RawData())
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+
+	target, err := e.AddCommit(mtag.Target)
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+
+	tag := object.Tag{
+		Name:       name,
+		Tagger:     signature(mtag.Tagger, "", "", mtag.Date, ""),
+		Message:    mtag.Message,
+		TargetType: plumbing.CommitObject,
+		Target:     target,
+	}
+
+	return e.addObject(&tag)
+}
+
+// AddCommit adds the commit with the given CID to the git repo.
+//
+// Commits that were imported from git are reused if the repo already contains them.
+// Imported commits without date metadata cannot be rebuilt and return an error.
+func (e *Exporter) AddCommit(id cid.Cid) (plumbing.Hash, error) {
+	if hash, ok := e.objects[id.String()]; ok {
+		return hash, nil
+	}
+
+	mcommit, err := mobject.GetCommit(e.ctx, e.dag, id)
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+
+	if h, ok := mcommit.Metadata[HashKey]; ok {
+		hash := plumbing.NewHash(h)
+		if err := e.repo.Storer.HasEncodedObject(hash); err == nil {
+			e.objects[id.String()] = hash
+			e.commits[hash] = id
+			return hash, nil
+		}
+
+		// older imports did not record dates so the original hash cannot be rebuilt
+		if mcommit.Metadata["git_author_date"] == "" || mcommit.Metadata["git_committer_date"] == "" {
+			return plumbing.ZeroHash, fmt.Errorf("commit %s was imported without dates; delete and import the repository again", h)
+		}
+	}
+
+	var parents []plumbing.Hash
+	for _, p := range mcommit.Parents {
+		parent, err := e.AddCommit(p)
+		if err != nil {
+			return plumbing.ZeroHash, err
+		}
+
+		parents = append(parents, parent)
+	}
+
+	node, err := e.dag.Get(e.ctx, mcommit.Tree)
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+
+	_, tree, err := e.AddNode(node)
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+
+	committer := mcommit.Committer
+	if committer == nil {
+		committer = mcommit.Author
+	}
+
+	meta := mcommit.Metadata
+	commit := object.Commit{
+		Author:       signature(mcommit.Author, meta["git_author_name"], meta["git_author_email"], mcommit.Date, meta["git_author_date"]),
+		Committer:    signature(committer, meta["git_committer_name"], meta["git_committer_email"], mcommit.Date, meta["git_committer_date"]),
+		PGPSignature: meta["git_signature"],
+		Message:      mcommit.Message,
+		TreeHash:     tree,
+		ParentHashes: parents,
+	}
+
+	hash, err := e.addObject(&commit)
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+
+	e.objects[id.String()] = hash
+	e.commits[hash] = id
+	return hash, nil
+}
+
+// AddNode adds the unixfs node to the git repo and returns its mode and hash.
+func (e *Exporter) AddNode(node ipld.Node) (filemode.FileMode, plumbing.Hash, error) {
+	if _, ok := node.(*merkledag.RawNode); ok {
+		hash, err := e.addBlob(node.Cid(), bytes.NewReader(node.RawData()))
+		return filemode.Regular, hash, err
+	}
+
+	fsnode, err := ufs.ExtractFSNode(node)
+	if err != nil {
+		return filemode.Empty, plumbing.ZeroHash, err
+	}
+
+	switch fsnode.Type() {
+	case ufs.TDirectory, ufs.THAMTShard:
+		hash, err := e.AddTree(node)
+		return filemode.Dir, hash, err
+	case ufs.TSymlink:
+		hash, err := e.addBlob(node.Cid(), bytes.NewReader(fsnode.Data()))
+		return filemode.Symlink, hash, err
+	case ufs.TFile, ufs.TRaw:
+		mode, err := dag.Mode(node)
+		if err != nil {
+			return filemode.Empty, plumbing.ZeroHash, err
+		}
+
+		r, err := ufsio.NewDagReader(e.ctx, node, e.dag)
+		if err != nil {
+			return filemode.Empty, plumbing.ZeroHash, err
+		}
+		defer r.Close()
+
+		hash, err := e.addBlob(node.Cid(), r)
+		if mode&0111 != 0 {
+			return filemode.Executable, hash, err
+		}
+
+		return filemode.Regular, hash, err
+	default:
+		return filemode.Empty, plumbing.ZeroHash, errors.New("invalid file type")
+	}
+}
+
+// AddTree adds the unixfs directory to the git repo.
+//
+// Empty directories are skipped because git does not track them.
+func (e *Exporter) AddTree(node ipld.Node) (plumbing.Hash, error) {
+	if hash, ok := e.objects[node.Cid().String()]; ok {
+		return hash, nil
+	}
+
+	dir, err := ufsio.NewDirectoryFromNode(e.dag, node)
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+
+	links, err := dir.Links(e.ctx)
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+
+	var entries []object.TreeEntry
+	for _, link := range links {
+		subnode, err := link.GetNode(e.ctx, e.dag)
+		if err != nil {
+			return plumbing.ZeroHash, err
+		}
+
+		mode, hash, err := e.AddNode(subnode)
+		if err != nil {
+			return plumbing.ZeroHash, err
+		}
+
+		if mode == filemode.Dir && hash == emptyTree {
+			continue
+		}
+
+		entries = append(entries, object.TreeEntry{
+			Name: link.Name,
+			Mode: mode,
+			Hash: hash,
+		})
+	}
+
+	// git sorts directories as if their names end with a slash
+	sort.Slice(entries, func(a, b int) bool {
+		return treeEntryName(entries[a]) < treeEntryName(entries[b])
+	})
+
+	hash, err := e.addObject(&object.Tree{Entries: entries})
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+
+	e.objects[node.Cid().String()] = hash
+	return hash, nil
+}
+
+// addBlob adds the contents of the reader as a blob to the git repo.
+func (e *Exporter) addBlob(id cid.Cid, r io.Reader) (plumbing.Hash, error) {
+	if hash, ok := e.objects[id.String()]; ok {
+		return hash, nil
+	}
+
+	obj := e.repo.Storer.NewEncodedObject()
+	obj.SetType(plumbing.BlobObject)
+
+	w, err := obj.Writer()
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+
+	if _, err := io.Copy(w, r); err != nil {
+		return plumbing.ZeroHash, err
+	}
+
+	if err := w.Close(); err != nil {
+		return plumbing.ZeroHash, err
+	}
+
+	hash, err := e.repo.Storer.SetEncodedObject(obj)
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+
+	e.objects[id.String()] = hash
+	return hash, nil
+}
+
+// encoder is a git object that can be encoded.
+type encoder interface {
+	Encode(plumbing.EncodedObject) error
+}
+
+// addObject encodes the object and adds it to the git repo.
+func (e *Exporter) addObject(obj encoder) (plumbing.Hash, error) {
+	enc := e.repo.Storer.NewEncodedObject()
+	if err := obj.Encode(enc); err != nil {
+		return plumbing.ZeroHash, err
+	}
+
+	return e.repo.Storer.SetEncodedObject(enc)
+}
+
+// emptyTree is the hash of a git tree with no entries.
+var emptyTree = plumbing.NewHash("4b825dc642cb6eb9a060e54bf8d69288fbee4904")
+
+// firstBranch returns the first branch name in sorted order or an empty string if there are no branches.
+func firstBranch(mrepo *mobject.Repository) string {
+	var names []string
+	for name := range mrepo.Branches {
+		names = append(names, name)
+	}
+
+	if len(names) == 0 {
+		return ""
+	}
+
+	sort.Strings(names)
+	return names[0]
+}
+
+// treeEntryName returns the name used to sort the tree entry.
+func treeEntryName(entry object.TreeEntry) string {
+	if entry.Mode == filemode.Dir {
+		return entry.Name + "/"
+	}
+
+	return entry.Name
+}
+
+// signature returns a git signature from the identity.
+//
+// The name, email, and date are used instead of the identity when set.
+func signature(id *mobject.Identity, name, email string, when time.Time, date string) object.Signature {
+	if id != nil && name == "" {
+		name = id.Name
+	}
+
+	if id != nil && email == "" {
+		email = id.Email
+	}
+
+	if t, err := time.Parse(DateFormat, date); err == nil {
+		when = t
+	}
+
+	return object.Signature{
+		Name:  name,
+		Email: email,
+		When:  when,
+	}
+}
@@ -0,0 +1,148 @@
+package git
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/ipfs/go-merkledag/dagutils"
+	ufsio "github.com/ipfs/go-unixfs/io"
+
+	mobject "github.com/multiverse-vcs/go-multiverse/pkg/object"
+)
+
+func TestExportRoundtrip(t *testing.T) {
+	ctx := context.Background()
+	mem := dagutils.NewMemoryDagService()
+
+	dir, err := ioutil.TempDir("", "git-*")
+	if err != nil {
+		t.Fatal("failed to create temp dir")
+	}
+	defer os.RemoveAll(dir)
+
+	src := filepath.Join(dir, "src")
+	repo, err := git.PlainInit(src, false)
+	if err != nil {
+		t.Fatal("failed to init git repo")
+	}
+
+	if err := os.MkdirAll(filepath.Join(src, "sub", "dir"), 0755); err != nil {
+		t.Fatal("failed to create dir")
+	}
+
+	if err := os.WriteFile(filepath.Join(src, "sub", "dir", "run.sh"), []byte("#!/bin/sh"), 0755); err != nil {
+		t.Fatal("failed to write file")
+	}
+
+	if err := os.Symlink("sub/dir/run.sh", filepath.Join(src, "link")); err != nil {
+		t.Fatal("failed to create symlink")
+	}
+
+	first := commitFile(t, repo, src, "first")
+
+	tree, err := repo.Worktree()
+	if err != nil {
+		t.Fatal("failed to get worktree")
+	}
+
+	if _, err := tree.Add("."); err != nil {
+		t.Fatal("failed to add files")
+	}
+
+	author := &object.Signature{Name: "author", Email: "author@example.com", When: time.Unix(1000, 0).In(time.FixedZone("", 3600))}
+	committer := &object.Signature{Name: "committer", Email: "committer@example.com", When: time.Unix(2000, 0).In(time.FixedZone("", -7200))}
+
+	second, err := tree.Commit("second\n", &git.CommitOptions{Author: author, Committer: committer})
+	if err != nil {
+		t.Fatal("failed to commit")
+	}
+
+	if _, err := repo.CreateTag("v1", first, nil); err != nil {
+		t.Fatal("failed to create tag")
+	}
+
+	repoID, err := ImportFromFS(ctx, mem, "test", src)
+	if err != nil {
+		t.Fatalf("failed to import git repo %v", err)
+	}
+
+	mrepo, err := mobject.GetRepository(ctx, mem, repoID)
+	if err != nil {
+		t.Fatal("failed to get repository")
+	}
+
+	dst := filepath.Join(dir, "dst")
+	if err := ExportToFS(ctx, mem, mrepo, dst); err != nil {
+		t.Fatalf("failed to export repository %v", err)
+	}
+
+	export, err := git.PlainOpen(dst)
+	if err != nil {
+		t.Fatal("failed to open exported repo")
+	}
+
+	head, err := export.Head()
+	if err != nil {
+		t.Fatal("failed to get head")
+	}
+
+	if head.Hash() != second {
+		t.Errorf("expected head %s got %s", second, head.Hash())
+	}
+
+	tag, err := export.Reference(plumbing.NewTagReferenceName("v1"), false)
+	if err != nil {
+		t.Fatal("failed to get tag")
+	}
+
+	if tag.Hash() != first {
+		t.Errorf("expected tag %s got %s", first, tag.Hash())
+	}
+}
+
+func TestExportMissingDates(t *testing.T) {
+	ctx := context.Background()
+	mem := dagutils.NewMemoryDagService()
+
+	dir, err := ioutil.TempDir("", "git-*")
+	if err != nil {
+		t.Fatal("failed to create temp dir")
+	}
+	defer os.RemoveAll(dir)
+
+	repo, err := git.PlainInit(dir, true)
+	if err != nil {
+		t.Fatal("failed to init git repo")
+	}
+
+	tree, err := ufsio.NewDirectory(mem).GetNode()
+	if err != nil {
+		t.Fatal("failed to create tree")
+	}
+
+	if err := mem.Add(ctx, tree); err != nil {
+		t.Fatal("failed to add tree")
+	}
+
+	// commits imported before dates were recorded only have a hash
+	commit := mobject.NewCommit()
+	commit.Tree = tree.Cid()
+	commit.Message = "first"
+	commit.Metadata[HashKey] = "2f1a0b6a3d9a41d1c4f0bd1c5cba1c4d0f0d6c1e"
+
+	id, err := mobject.AddCommit(ctx, mem, commit)
+	if err != nil {
+		t.Fatal("failed to add commit")
+	}
+
+	if _, err := NewExporter(ctx, mem, repo).AddCommit(id); err == nil {
+		t.Error("expected export to fail")
+	}
+}
@@ -3,12 +3,14 @@ package git
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"os"
 	"path"
 	"path/filepath"
 
 	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
 	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/filemode"
 	"github.com/go-git/go-git/v5/plumbing/object"
@@ -19,14 +21,23 @@ import (
 	ufsio "github.com/ipfs/go-unixfs/io"
 
 	"github.com/multiverse-vcs/go-multiverse/pkg/dag"
+	"github.com/multiverse-vcs/go-multiverse/pkg/merge"
 	mobject "github.com/multiverse-vcs/go-multiverse/pkg/object"
 )
 
-// DateFormat is the format to store dates in.
-const DateFormat = "Mon Jan 02 15:04:05 2006 -0700"
+const (
+	// DateFormat is the format to store dates in.
+	DateFormat = "Mon Jan 02 15:04:05 2006 -0700"
+	// HashKey is the commit metadata key of the git commit hash.
+	HashKey = "git_hash"
+	// URLKey is the repository metadata key of the imported git url.
+	URLKey = "git_url"
+	// PathKey is the repository metadata key of the imported git directory.
+	PathKey = "git_path"
+)
 
-// importer adds objects from a git repo to a dag.
-type importer struct {
+// Importer adds objects from a git repo to a dag.
+type Importer struct {
 	ctx      context.Context
 	dag      ipld.DAGService
 	name     string
@@ -34,38 +45,75 @@ type importer struct {
 	objects  map[string]cid.Cid
 	branches map[string]cid.Cid
 	tags     map[string]cid.Cid
+	metadata map[string]string
+	prev     map[string]cid.Cid
+	force    bool
 }
 
 // ImportFromURL is a helper to import a git repo from a url.
 func ImportFromURL(ctx context.Context, dag ipld.DAGService, name, url string) (cid.Cid, error) {
+	return UpdateFromURL(ctx, dag, nil, name, url, false)
+}
+
+// ImportFromFS is a helper to import a git repo from a directory.
+func ImportFromFS(ctx context.Context, dag ipld.DAGService, name, dir string) (cid.Cid, error) {
+	return UpdateFromFS(ctx, dag, nil, name, dir, false)
+}
+
+// UpdateFromURL is a helper to import only new commits from a git repo url
+// into a previously imported repository. If prev is nil all commits are imported.
+//
+// A mirror of the url is kept in the temp dir so that updates only fetch new objects.
+//
+// Branches that are not fast-forwards of prev are only overwritten if force is set.
+func UpdateFromURL(ctx context.Context, dag ipld.DAGService, prev *mobject.Repository, name, url string, force bool) (cid.Cid, error) {
 	dir := filepath.Join(os.TempDir(), "multi_git_import_"+name)
-	defer os.RemoveAll(dir)
 
-	opts := git.CloneOptions{
-		URL: url,
+	repo, err := fetchMirror(ctx, dir, url)
+	if err != nil {
+		return cid.Cid{}, err
 	}
 
-	repo, err := git.PlainClone(dir, true, &opts)
-	if err != nil {
+	i := NewImporter(ctx, dag, repo, name)
+	if err := i.LoadRepository(prev); err != nil {
 		return cid.Cid{}, err
 	}
 
-	return NewImporter(ctx, dag, repo, name).AddRepository()
+	i.force = force
+	delete(i.metadata, PathKey)
+	i.metadata[URLKey] = url
+	return i.AddRepository()
 }
 
-// ImportFromFS is a helper to import a git repo from a directory.
-func ImportFromFS(ctx context.Context, dag ipld.DAGService, name, dir string) (cid.Cid, error) {
+// UpdateFromFS is a helper to import only new commits from a git repo directory
+// into a previously imported repository. If prev is nil all commits are imported.
+//
+// Branches that are not fast-forwards of prev are only overwritten if force is set.
+func UpdateFromFS(ctx context.Context, dag ipld.DAGService, prev *mobject.Repository, name, dir string, force bool) (cid.Cid, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return cid.Cid{}, err
+	}
+
 	repo, err := git.PlainOpen(dir)
 	if err != nil {
 		return cid.Cid{}, err
 	}
 
-	return NewImporter(ctx, dag, repo, name).AddRepository()
+	i := NewImporter(ctx, dag, repo, name)
+	if err := i.LoadRepository(prev); err != nil {
+		return cid.Cid{}, err
+	}
+
+	i.force = force
+	delete(i.metadata, URLKey)
+	i.metadata[PathKey] = dir
+	return i.AddRepository()
 }
 
 // NewImporter returns an importer for the given repo.
-func NewImporter(ctx context.Context, dag ipld.DAGService, repo *git.Repository, name string) *importer {
-	return &importer{
+func NewImporter(ctx context.Context, dag ipld.DAGService, repo *git.Repository, name string) *Importer {
+	return &Importer{
 		ctx:      ctx,
 		dag:      dag,
 		name:     name,
@@ -73,11 +121,157 @@ func NewImporter(ctx context.Context, dag ipld.DAGService, repo *git.Repository,
 		objects:  make(map[string]cid.Cid),
 		branches: make(map[string]cid.Cid),
 		tags:     make(map[string]cid.Cid),
+		metadata: make(map[string]string),
+		prev:     make(map[string]cid.Cid),
+	}
+}
+
+// LoadRepository adds the git hashes of all commits in the repo to the importer
+// so that previously imported commits are not added again.
+func (i *Importer) LoadRepository(repo *mobject.Repository) error {
+	if repo == nil {
+		return nil
+	}
+
+	for k, v := range repo.Metadata {
+		i.metadata[k] = v
+	}
+
+	// the trees of the heads are loaded so unchanged files are not imported again
+	heads := cid.NewSet()
+
+	var stack []cid.Cid
+	for name, id := range repo.Branches {
+		i.prev[name] = id
+		heads.Add(id)
+		stack = append(stack, id)
+	}
+
+	for _, id := range repo.Tags {
+		heads.Add(id)
+		stack = append(stack, id)
+	}
+
+	seen := cid.NewSet()
+	for len(stack) > 0 {
+		id := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		if !seen.Visit(id) {
+			continue
+		}
+
+		node, err := i.dag.Get(i.ctx, id)
+		if err != nil {
+			return err
+		}
+
+		commit, err := mobject.CommitFromCBOR(node.RawData())
+		if err != nil {
+			// tags created after import are not commits
+			tag, terr := mobject.TagFromCBOR(node.RawData())
+			if terr != nil {
+				return err
+			}
+
+			heads.Add(tag.Target)
+			stack = append(stack, tag.Target)
+			continue
+		}
+
+		if hash, ok := commit.Metadata[HashKey]; ok {
+			i.objects[hash] = id
+		}
+
+		if hash, ok := commit.Metadata[HashKey]; ok && heads.Has(id) {
+			if err := i.loadCommitTree(plumbing.NewHash(hash), commit.Tree); err != nil {
+				return err
+			}
+		}
+
+		stack = append(stack, commit.Parents...)
+	}
+
+	return nil
+}
+
+// loadCommitTree maps the tree of the git commit to the tree of the imported commit.
+//
+// Commits that are no longer in the git repo are skipped.
+func (i *Importer) loadCommitTree(hash plumbing.Hash, id cid.Cid) error {
+	commit, err := i.repo.CommitObject(hash)
+	if err == plumbing.ErrObjectNotFound {
+		return nil
+	}
+
+	if err != nil {
+		return err
 	}
+
+	return i.loadTree(commit.TreeHash, id)
+}
+
+// loadTree maps the git tree and all of its entries to the imported unixfs directory.
+func (i *Importer) loadTree(hash plumbing.Hash, id cid.Cid) error {
+	if _, ok := i.objects[hash.String()]; ok {
+		return nil
+	}
+
+	tree, err := i.repo.TreeObject(hash)
+	if err != nil {
+		return err
+	}
+
+	node, err := i.dag.Get(i.ctx, id)
+	if err != nil {
+		return err
+	}
+
+	dir, err := ufsio.NewDirectoryFromNode(i.dag, node)
+	if err != nil {
+		return err
+	}
+
+	links, err := dir.Links(i.ctx)
+	if err != nil {
+		return err
+	}
+
+	ids := make(map[string]cid.Cid)
+	for _, link := range links {
+		ids[link.Name] = link.Cid
+	}
+
+	for _, entry := range tree.Entries {
+		subID, ok := ids[entry.Name]
+		if !ok {
+			continue
+		}
+
+		switch entry.Mode {
+		case filemode.Dir:
+			if err := i.loadTree(entry.Hash, subID); err != nil {
+				return err
+			}
+		case filemode.Submodule:
+		default:
+			i.objects[entryKey(entry)] = subID
+		}
+	}
+
+	i.objects[hash.String()] = id
+	return nil
+}
+
+// SetObject records that the git object with the given hash was added to the dag as id.
+//
+// This allows objects that were not imported from git to be reused.
+func (i *Importer) SetObject(hash plumbing.Hash, id cid.Cid) {
+	i.objects[hash.String()] = id
 }
 
 // AddRepository adds all branches and tags to the dag.
-func (i *importer) AddRepository() (cid.Cid, error) {
+func (i *Importer) AddRepository() (cid.Cid, error) {
 	head, err := i.repo.Head()
 	if err != nil {
 		return cid.Cid{}, err
@@ -109,11 +303,17 @@ func (i *importer) AddRepository() (cid.Cid, error) {
 	mrepo.Tags = i.tags
 	mrepo.DefaultBranch = defaultBranch
 
+	for k, v := range i.metadata {
+		mrepo.Metadata[k] = v
+	}
+
 	return mobject.AddRepository(i.ctx, i.dag, mrepo)
 }
 
 // AddBranch adds the branch with the given ref to the dag.
-func (i *importer) AddBranch(ref *plumbing.Reference) error {
+//
+// Loaded branches can only move to descendants of their previous head unless forced.
+func (i *Importer) AddBranch(ref *plumbing.Reference) error {
 	id, err := i.AddCommit(ref.Hash())
 	if err != nil {
 		return err
@@ -122,12 +322,24 @@ func (i *importer) AddBranch(ref *plumbing.Reference) error {
 	name := string(ref.Name())
 	name = path.Base(name)
 
+	prev, ok := i.prev[name]
+	if ok && !i.force {
+		base, err := merge.Base(i.ctx, i.dag, prev, id)
+		if err != nil {
+			return err
+		}
+
+		if base != prev {
+			return fmt.Errorf("branch %s is not a fast-forward", name)
+		}
+	}
+
 	i.branches[name] = id
 	return nil
 }
 
 // AddTag adds the tag with the given ref to the dag.
-func (i *importer) AddTag(ref *plumbing.Reference) error {
+func (i *Importer) AddTag(ref *plumbing.Reference) error {
 	id, ok := i.objects[ref.Hash().String()]
 	if !ok {
 		return nil
@@ -141,7 +353,7 @@ func (i *importer) AddTag(ref *plumbing.Reference) error {
 }
 
 // AddCommit adds the commit with the given hash to the dag.
-func (i *importer) AddCommit(hash plumbing.Hash) (cid.Cid, error) {
+func (i *Importer) AddCommit(hash plumbing.Hash) (cid.Cid, error) {
 	if id, ok := i.objects[hash.String()]; ok {
 		return id, nil
 	}
@@ -171,11 +383,19 @@ func (i *importer) AddCommit(hash plumbing.Hash) (cid.Cid, error) {
 	mcommit.Message = commit.Message
 	mcommit.Parents = parents
 	mcommit.Date = commit.Committer.When
-	mcommit.Metadata["git_hash"] = hash.String()
+	mcommit.Author = &mobject.Identity{Name: commit.Author.Name, Email: commit.Author.Email}
+	mcommit.Committer = &mobject.Identity{Name: commit.Committer.Name, Email: commit.Committer.Email}
+	mcommit.Metadata[HashKey] = hash.String()
 	mcommit.Metadata["git_author_name"] = commit.Author.Name
 	mcommit.Metadata["git_author_email"] = commit.Author.Email
 	mcommit.Metadata["git_committer_name"] = commit.Committer.Name
 	mcommit.Metadata["git_committer_email"] = commit.Committer.Email
+	mcommit.Metadata["git_author_date"] = commit.Author.When.Format(DateFormat)
+	mcommit.Metadata["git_committer_date"] = commit.Committer.When.Format(DateFormat)
+
+	if commit.PGPSignature != "" {
+		mcommit.Metadata["git_signature"] = commit.PGPSignature
+	}
 
 	id, err := mobject.AddCommit(i.ctx, i.dag, mcommit)
 	if err != nil {
@@ -187,7 +407,7 @@ func (i *importer) AddCommit(hash plumbing.Hash) (cid.Cid, error) {
 }
 
 // AddTree adds the tree with the given hash to the dag.
-func (i *importer) AddTree(hash plumbing.Hash) (ipld.Node, error) {
+func (i *Importer) AddTree(hash plumbing.Hash) (ipld.Node, error) {
 	if id, ok := i.objects[hash.String()]; ok {
 		return i.dag.Get(i.ctx, id)
 	}
@@ -223,7 +443,7 @@ func (i *importer) AddTree(hash plumbing.Hash) (ipld.Node, error) {
 }
 
 // AddTreeEntry adds the tree entry with the given hash to the dag.
-func (i *importer) AddTreeEntry(entry object.TreeEntry) (ipld.Node, error) {
+func (i *Importer) AddTreeEntry(entry object.TreeEntry) (ipld.Node, error) {
 	switch entry.Mode {
 	case filemode.Dir:
 		return i.AddTree(entry.Hash)
@@ -231,6 +451,21 @@ func (i *importer) AddTreeEntry(entry object.TreeEntry) (ipld.Node, error) {
 		return ufs.EmptyDirNode(), nil
 	}
 
+	if id, ok := i.objects[entryKey(entry)]; ok {
+		return i.dag.Get(i.ctx, id)
+	}
+
+	node, err := i.addBlob(entry)
+	if err != nil {
+		return nil, err
+	}
+
+	i.objects[entryKey(entry)] = node.Cid()
+	return node, nil
+}
+
+// addBlob adds the file or symlink of the tree entry to the dag.
+func (i *Importer) addBlob(entry object.TreeEntry) (ipld.Node, error) {
 	blob, err := i.repo.BlobObject(entry.Hash)
 	if err != nil {
 		return nil, err
@@ -242,7 +477,15 @@ func (i *importer) AddTreeEntry(entry object.TreeEntry) (ipld.Node, error) {
 	}
 	defer r.Close()
 
-	if entry.Mode.IsFile() {
+	switch entry.Mode {
+	case filemode.Executable:
+		node, err := dag.Chunk(i.ctx, i.dag, r)
+		if err != nil {
+			return nil, err
+		}
+
+		return dag.SetMode(i.ctx, i.dag, node, 0755)
+	case filemode.Regular, filemode.Deprecated:
 		return dag.Chunk(i.ctx, i.dag, r)
 	}
 
@@ -251,10 +494,120 @@ func (i *importer) AddTreeEntry(entry object.TreeEntry) (ipld.Node, error) {
 		return nil, err
 	}
 
-	node := merkledag.NodeWithData(target)
+	data, err := ufs.SymlinkData(string(target))
+	if err != nil {
+		return nil, err
+	}
+
+	node := merkledag.NodeWithData(data)
 	if err := i.dag.Add(i.ctx, node); err != nil {
 		return nil, err
 	}
 
 	return node, nil
 }
+
+// entryKey returns the object key of a blob tree entry.
+//
+// The mode is part of the key because it changes the imported node.
+func entryKey(entry object.TreeEntry) string {
+	return entry.Mode.String() + " " + entry.Hash.String()
+}
+
+// mirrorRefSpecs copy all branches and tags from the remote.
+var mirrorRefSpecs = []config.RefSpec{
+	"+refs/heads/*:refs/heads/*",
+	"+refs/tags/*:refs/tags/*",
+}
+
+// fetchMirror updates the bare mirror of the url in dir and creates it if needed.
+//
+// Branches and tags that were deleted from the remote are removed from the mirror.
+func fetchMirror(ctx context.Context, dir, url string) (*git.Repository, error) {
+	repo, err := git.PlainOpen(dir)
+	if err == nil {
+		remote, rerr := repo.Remote(git.DefaultRemoteName)
+		if rerr != nil || remote.Config().URLs[0] != url {
+			repo, err = nil, git.ErrRepositoryNotExists
+		}
+	}
+
+	if err == git.ErrRepositoryNotExists {
+		repo, err = initMirror(dir, url)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	opts := git.FetchOptions{
+		RefSpecs: mirrorRefSpecs,
+		Tags:     git.NoTags,
+		Force:    true,
+	}
+
+	err = repo.FetchContext(ctx, &opts)
+	if err != nil && err != git.NoErrAlreadyUpToDate {
+		return nil, err
+	}
+
+	remote, err := repo.Remote(git.DefaultRemoteName)
+	if err != nil {
+		return nil, err
+	}
+
+	refs, err := remote.List(&git.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[plumbing.ReferenceName]bool)
+	for _, ref := range refs {
+		names[ref.Name()] = true
+
+		if ref.Name() == plumbing.HEAD && ref.Type() == plumbing.SymbolicReference {
+			if err := repo.Storer.SetReference(ref); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	iter, err := repo.References()
+	if err != nil {
+		return nil, err
+	}
+
+	err = iter.ForEach(func(ref *plumbing.Reference) error {
+		if !ref.Name().IsBranch() && !ref.Name().IsTag() || names[ref.Name()] {
+			return nil
+		}
+
+		return repo.Storer.RemoveReference(ref.Name())
+	})
+
+	return repo, err
+}
+
+// initMirror creates an empty bare repo in dir with a remote for the url.
+func initMirror(dir, url string) (*git.Repository, error) {
+	if err := os.RemoveAll(dir); err != nil {
+		return nil, err
+	}
+
+	repo, err := git.PlainInit(dir, true)
+	if err != nil {
+		return nil, err
+	}
+
+	remote := config.RemoteConfig{
+		Name:  git.DefaultRemoteName,
+		URLs:  []string{url},
+		Fetch: mirrorRefSpecs,
+	}
+
+	if _, err := repo.CreateRemote(&remote); err != nil {
+		return nil, err
+	}
+
+	return repo, nil
+}
@@ -2,9 +2,18 @@ package git
 
 import (
 	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/ipfs/go-merkledag/dagutils"
+
+	mobject "github.com/multiverse-vcs/go-multiverse/pkg/object"
 )
 
 func TestImportFromURL(t *testing.T) {
@@ -17,3 +26,268 @@ func TestImportFromURL(t *testing.T) {
 		t.Fatalf("failed to import git repo %v", err)
 	}
 }
+
+func TestUpdateFromFS(t *testing.T) {
+	ctx := context.Background()
+	mem := dagutils.NewMemoryDagService()
+
+	dir, err := ioutil.TempDir("", "git-*")
+	if err != nil {
+		t.Fatal("failed to create temp dir")
+	}
+	defer os.RemoveAll(dir)
+
+	repo, err := git.PlainInit(dir, false)
+	if err != nil {
+		t.Fatal("failed to init git repo")
+	}
+
+	first := commitFile(t, repo, dir, "first")
+
+	prevID, err := ImportFromFS(ctx, mem, "test", dir)
+	if err != nil {
+		t.Fatalf("failed to import git repo %v", err)
+	}
+
+	prev, err := mobject.GetRepository(ctx, mem, prevID)
+	if err != nil {
+		t.Fatal("failed to get repository")
+	}
+
+	if prev.Metadata[PathKey] != dir {
+		t.Errorf("expected path metadata to be set")
+	}
+
+	second := commitFile(t, repo, dir, "second")
+	if _, err := repo.CreateTag("v1", first, nil); err != nil {
+		t.Fatal("failed to create tag")
+	}
+
+	nextID, err := UpdateFromFS(ctx, mem, prev, "test", dir, false)
+	if err != nil {
+		t.Fatalf("failed to update git repo %v", err)
+	}
+
+	next, err := mobject.GetRepository(ctx, mem, nextID)
+	if err != nil {
+		t.Fatal("failed to get repository")
+	}
+
+	branch := prev.DefaultBranch
+	commit, err := mobject.GetCommit(ctx, mem, next.Branches[branch])
+	if err != nil {
+		t.Fatal("failed to get commit")
+	}
+
+	if commit.Metadata[HashKey] != second.String() {
+		t.Errorf("expected branch to move to new commit")
+	}
+
+	if len(commit.Parents) != 1 || commit.Parents[0] != prev.Branches[branch] {
+		t.Errorf("expected parent to reuse imported commit")
+	}
+
+	if next.Tags["v1"] != prev.Branches[branch] {
+		t.Errorf("expected tag to reuse imported commit")
+	}
+}
+
+func TestUpdateFromFSForce(t *testing.T) {
+	ctx := context.Background()
+	mem := dagutils.NewMemoryDagService()
+
+	dir, err := ioutil.TempDir("", "git-*")
+	if err != nil {
+		t.Fatal("failed to create temp dir")
+	}
+	defer os.RemoveAll(dir)
+
+	repo, err := git.PlainInit(dir, false)
+	if err != nil {
+		t.Fatal("failed to init git repo")
+	}
+
+	first := commitFile(t, repo, dir, "first")
+	commitFile(t, repo, dir, "second")
+
+	prevID, err := ImportFromFS(ctx, mem, "test", dir)
+	if err != nil {
+		t.Fatalf("failed to import git repo %v", err)
+	}
+
+	prev, err := mobject.GetRepository(ctx, mem, prevID)
+	if err != nil {
+		t.Fatal("failed to get repository")
+	}
+
+	tree, err := repo.Worktree()
+	if err != nil {
+		t.Fatal("failed to get worktree")
+	}
+
+	// rewrite history so the branch diverges from the imported head
+	if err := tree.Reset(&git.ResetOptions{Commit: first, Mode: git.HardReset}); err != nil {
+		t.Fatal("failed to reset worktree")
+	}
+
+	third := commitFile(t, repo, dir, "third")
+
+	if _, err := UpdateFromFS(ctx, mem, prev, "test", dir, false); err == nil {
+		t.Fatal("expected update to fail")
+	}
+
+	nextID, err := UpdateFromFS(ctx, mem, prev, "test", dir, true)
+	if err != nil {
+		t.Fatalf("failed to force update git repo %v", err)
+	}
+
+	next, err := mobject.GetRepository(ctx, mem, nextID)
+	if err != nil {
+		t.Fatal("failed to get repository")
+	}
+
+	commit, err := mobject.GetCommit(ctx, mem, next.Branches[prev.DefaultBranch])
+	if err != nil {
+		t.Fatal("failed to get commit")
+	}
+
+	if commit.Metadata[HashKey] != third.String() {
+		t.Errorf("expected branch to be overwritten")
+	}
+}
+
+func TestUpdateFromURL(t *testing.T) {
+	ctx := context.Background()
+	mem := dagutils.NewMemoryDagService()
+
+	dir, err := ioutil.TempDir("", "git-*")
+	if err != nil {
+		t.Fatal("failed to create temp dir")
+	}
+	defer os.RemoveAll(dir)
+
+	name := filepath.Base(dir)
+	defer os.RemoveAll(filepath.Join(os.TempDir(), "multi_git_import_"+name))
+
+	repo, err := git.PlainInit(dir, false)
+	if err != nil {
+		t.Fatal("failed to init git repo")
+	}
+
+	commitFile(t, repo, dir, "first")
+
+	prevID, err := ImportFromURL(ctx, mem, name, dir)
+	if err != nil {
+		t.Fatalf("failed to import git repo %v", err)
+	}
+
+	prev, err := mobject.GetRepository(ctx, mem, prevID)
+	if err != nil {
+		t.Fatal("failed to get repository")
+	}
+
+	second := commitFile(t, repo, dir, "second")
+
+	nextID, err := UpdateFromURL(ctx, mem, prev, name, dir, false)
+	if err != nil {
+		t.Fatalf("failed to update git repo %v", err)
+	}
+
+	next, err := mobject.GetRepository(ctx, mem, nextID)
+	if err != nil {
+		t.Fatal("failed to get repository")
+	}
+
+	commit, err := mobject.GetCommit(ctx, mem, next.Branches[prev.DefaultBranch])
+	if err != nil {
+		t.Fatal("failed to get commit")
+	}
+
+	if commit.Metadata[HashKey] != second.String() {
+		t.Errorf("expected branch to move to fetched commit")
+	}
+
+	if len(commit.Parents) != 1 || commit.Parents[0] != prev.Branches[prev.DefaultBranch] {
+		t.Errorf("expected parent to reuse imported commit")
+	}
+}
+
+func TestLoadRepositoryTrees(t *testing.T) {
+	ctx := context.Background()
+	mem := dagutils.NewMemoryDagService()
+
+	dir, err := ioutil.TempDir("", "git-*")
+	if err != nil {
+		t.Fatal("failed to create temp dir")
+	}
+	defer os.RemoveAll(dir)
+
+	repo, err := git.PlainInit(dir, false)
+	if err != nil {
+		t.Fatal("failed to init git repo")
+	}
+
+	hash := commitFile(t, repo, dir, "first")
+
+	prevID, err := ImportFromFS(ctx, mem, "test", dir)
+	if err != nil {
+		t.Fatalf("failed to import git repo %v", err)
+	}
+
+	prev, err := mobject.GetRepository(ctx, mem, prevID)
+	if err != nil {
+		t.Fatal("failed to get repository")
+	}
+
+	i := NewImporter(ctx, mem, repo, "test")
+	if err := i.LoadRepository(prev); err != nil {
+		t.Fatalf("failed to load repository %v", err)
+	}
+
+	commit, err := repo.CommitObject(hash)
+	if err != nil {
+		t.Fatal("failed to get commit")
+	}
+
+	tree, err := commit.Tree()
+	if err != nil {
+		t.Fatal("failed to get tree")
+	}
+
+	head, err := mobject.GetCommit(ctx, mem, prev.Branches[prev.DefaultBranch])
+	if err != nil {
+		t.Fatal("failed to get commit")
+	}
+
+	if i.objects[tree.Hash.String()] != head.Tree {
+		t.Errorf("expected tree to be mapped to imported tree")
+	}
+
+	if _, ok := i.objects[entryKey(tree.Entries[0])]; !ok {
+		t.Errorf("expected file to be mapped to imported file")
+	}
+}
+
+// commitFile writes a file to the git worktree and commits it.
+func commitFile(t *testing.T, repo *git.Repository, dir, content string) plumbing.Hash {
+	if err := os.WriteFile(filepath.Join(dir, "README"), []byte(content), 0644); err != nil {
+		t.Fatal("failed to write file")
+	}
+
+	tree, err := repo.Worktree()
+	if err != nil {
+		t.Fatal("failed to get worktree")
+	}
+
+	if _, err := tree.Add("README"); err != nil {
+		t.Fatal("failed to add file")
+	}
+
+	sig := &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()}
+	hash, err := tree.Commit(content, &git.CommitOptions{Author: sig})
+	if err != nil {
+		t.Fatal("failed to commit")
+	}
+
+	return hash
+}
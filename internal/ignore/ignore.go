@@ -10,13 +10,17 @@ import (
 const IgnoreFile = ".multignore"
 
 // Filter is a list of paths to ignore.
-type Filter []Rule
+//
+// Rules are evaluated in order and the last matching rule wins.
+type Filter []*Rule
 
 // New returns a new ignore filter.
 func New(dir string, patterns ...string) Filter {
-	var rules []Rule
+	var rules []*Rule
 	for _, p := range patterns {
-		rules = append(rules, ParseRule(dir, p))
+		if rule := ParseRule(dir, p); rule != nil {
+			rules = append(rules, rule)
+		}
 	}
 
 	return Filter(rules)
@@ -24,7 +28,14 @@ func New(dir string, patterns ...string) Filter {
 
 // Load returns the ignore filter from the given directory.
 func Load(dir string) (Filter, error) {
-	data, err := os.ReadFile(filepath.Join(dir, IgnoreFile))
+	return LoadFile(dir, filepath.Join(dir, IgnoreFile))
+}
+
+// LoadFile returns the ignore filter from the file at path.
+//
+// Rules containing a slash are matched relative to dir.
+func LoadFile(dir, path string) (Filter, error) {
+	data, err := os.ReadFile(path)
 	if os.IsNotExist(err) {
 		return nil, nil
 	}
@@ -33,27 +44,45 @@ func Load(dir string) (Filter, error) {
 		return nil, err
 	}
 
-	patterns := strings.Split(string(data), "\n")
-	return New(dir, patterns...), nil
+	var rules []*Rule
+	for i, p := range strings.Split(string(data), "\n") {
+		rule := ParseRule(dir, p)
+		if rule == nil {
+			continue
+		}
+
+		rule.Source = path
+		rule.Line = i + 1
+		rules = append(rules, rule)
+	}
+
+	return Filter(rules), nil
+}
+
+// Match returns true if the path is ignored.
+//
+// A matching negated rule re-includes a path ignored by an earlier rule.
+func (f Filter) Match(name string, isDir bool) bool {
+	rule := f.Find(name, isDir)
+	return rule != nil && !rule.Negate
 }
 
-// Match returns true if the path matches any ignore rules.
-func (f Filter) Match(name string) bool {
-	for _, r := range f {
-		if match, _ := r.Match(name); match {
-			return true
+// Find returns the last rule that matches the path or nil if no rules match.
+func (f Filter) Find(name string, isDir bool) *Rule {
+	for i := len(f) - 1; i >= 0; i-- {
+		if f[i].Match(name, isDir) {
+			return f[i]
 		}
 	}
 
-	return false
+	return nil
 }
 
 // Merge combines the ignore rules with the other ignore.
+//
+// Rules from other take precedence over existing rules.
 func (f Filter) Merge(other Filter) Filter {
-	var merge Filter
-	for _, r := range other {
-		merge = append(merge, r)
-	}
-
-	return append(f, merge...)
+	merge := make(Filter, 0, len(f)+len(other))
+	merge = append(merge, f...)
+	return append(merge, other...)
 }
@@ -7,11 +7,11 @@ import (
 func TestMatch(t *testing.T) {
 	ignore := New("test", "*.exe")
 
-	if !ignore.Match("test/foo/bar.exe") {
+	if !ignore.Match("test/foo/bar.exe", false) {
 		t.Error("expected ignore to match")
 	}
 
-	if !ignore.Match("foo.exe") {
+	if !ignore.Match("foo.exe", false) {
 		t.Error("expected ignore to match")
 	}
 }
@@ -22,15 +22,15 @@ func TestLoad(t *testing.T) {
 		t.Fatal("failed to load ignore file")
 	}
 
-	if !ignore.Match("foo/bar") {
+	if !ignore.Match("foo/bar", false) {
 		t.Error("expected ignore to match")
 	}
 
-	if !ignore.Match("bar/foo") {
+	if !ignore.Match("bar/foo", false) {
 		t.Error("expected ignore to match")
 	}
 
-	if ignore.Match("foo.exe") {
+	if ignore.Match("foo.exe", false) {
 		t.Error("expected ignore not to match")
 	}
 }
@@ -44,15 +44,35 @@ func TestMerge(t *testing.T) {
 	other := New("test", "*.exe")
 	merge := ignore.Merge(other)
 
-	if !merge.Match("foo/bar") {
+	if !merge.Match("foo/bar", false) {
 		t.Error("expected ignore to match")
 	}
 
-	if !merge.Match("bar/foo") {
+	if !merge.Match("bar/foo", false) {
 		t.Error("expected ignore to match")
 	}
 
-	if !merge.Match("foo.exe") {
+	if !merge.Match("foo.exe", false) {
 		t.Error("expected ignore to match")
 	}
 }
+
+func TestFind(t *testing.T) {
+	ignore, err := Load("testdata")
+	if err != nil {
+		t.Fatal("failed to load ignore file")
+	}
+
+	rule := ignore.Find("testdata/foo/bar", false)
+	if rule == nil {
+		t.Fatal("expected rule to match")
+	}
+
+	if rule.Pattern != "/foo/bar" || rule.Line != 7 {
+		t.Errorf("unexpected rule %s:%d", rule.Pattern, rule.Line)
+	}
+
+	if rule.Source != "testdata/.multignore" {
+		t.Errorf("unexpected source %s", rule.Source)
+	}
+}
@@ -1,79 +1,179 @@
 package ignore
 
 import (
+	"path"
 	"path/filepath"
 	"strings"
 )
 
+// doubleStar matches zero or more directories.
+const doubleStar = "**"
+
 // Rule is used to match file paths.
-type Rule interface {
-	// Match returns true if the path matches.
-	Match(string) (bool, error)
+//
+// Patterns follow gitignore semantics.
+type Rule struct {
+	// Pattern is the original pattern text.
+	Pattern string
+	// Source is the file the rule was loaded from.
+	Source string
+	// Line is the line number of the rule in the source file.
+	Line int
+	// Negate is true if a matching path should be re-included.
+	Negate bool
+	// DirOnly is true if the rule only matches directories.
+	DirOnly bool
+
+	dir      string
+	anchored bool
+	segments []string
 }
 
-// compile time interface checks
-var _ Rule = (*NegateRule)(nil)
-var _ Rule = (BaseRule)("")
-var _ Rule = (PathRule)("")
-var _ Rule = (EmptyRule)("")
+// ParseRule returns a rule for the given pattern or nil if the pattern is blank or a comment.
+//
+// Rules containing a slash are matched relative to dir.
+func ParseRule(dir, pattern string) *Rule {
+	pattern = trimTrailingSpace(strings.TrimSuffix(pattern, "\r"))
+	if pattern == "" || strings.HasPrefix(pattern, "#") {
+		return nil
+	}
 
-// NegateRule negates a rule.
-type NegateRule struct {
-	wrapped Rule
-}
+	rule := Rule{
+		Pattern: pattern,
+		dir:     filepath.ToSlash(dir),
+	}
 
-// BaseRule matches the base of the path.
-type BaseRule string
+	if strings.HasPrefix(pattern, "!") {
+		rule.Negate = true
+		pattern = pattern[1:]
+	}
 
-// PathRule matches the entire path.
-type PathRule string
+	if strings.HasSuffix(pattern, "/") {
+		rule.DirOnly = true
+		pattern = strings.TrimRight(pattern, "/")
+	}
 
-// EmptyRule never matches.
-type EmptyRule string
+	if pattern == "" {
+		return nil
+	}
 
-// Match returns true if the path matches.
-func (r NegateRule) Match(name string) (bool, error) {
-	match, err := r.wrapped.Match(name)
-	return !match, err
-}
+	// a leading or middle slash anchors the pattern to dir
+	rule.anchored = strings.Contains(pattern, "/")
+	pattern = strings.TrimPrefix(pattern, "/")
 
-// Match returns true if the path matches.
-func (r BaseRule) Match(name string) (bool, error) {
-	base := filepath.Base(name)
-	return filepath.Match(string(r), base)
+	if !rule.anchored {
+		rule.segments = append(rule.segments, doubleStar)
+	}
+
+	for _, s := range strings.Split(pattern, "/") {
+		rule.segments = append(rule.segments, convertClass(s))
+	}
+
+	return &rule
 }
 
 // Match returns true if the path matches.
-func (r PathRule) Match(name string) (bool, error) {
-	return filepath.Match(string(r), name)
+//
+// The negation of the rule is not applied.
+func (r *Rule) Match(name string, isDir bool) bool {
+	if r.DirOnly && !isDir {
+		return false
+	}
+
+	name = filepath.ToSlash(name)
+	if r.anchored && r.dir != "" {
+		rel, ok := relative(r.dir, name)
+		if !ok {
+			return false
+		}
+
+		name = rel
+	}
+
+	name = strings.Trim(name, "/")
+	if name == "" || name == "." {
+		return false
+	}
+
+	return matchSegments(r.segments, strings.Split(name, "/"))
 }
 
-// Match returns true if the path matches.
-func (r EmptyRule) Match(name string) (bool, error) {
-	return false, nil
+// matchSegments returns true if the pattern segments match the path parts.
+func matchSegments(pattern, parts []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == doubleStar {
+			rest := pattern[1:]
+			if len(rest) == 0 {
+				return len(parts) > 0
+			}
+
+			for i := 0; i <= len(parts); i++ {
+				if matchSegments(rest, parts[i:]) {
+					return true
+				}
+			}
+
+			return false
+		}
+
+		if len(parts) == 0 {
+			return false
+		}
+
+		if match, err := path.Match(pattern[0], parts[0]); err != nil || !match {
+			return false
+		}
+
+		pattern, parts = pattern[1:], parts[1:]
+	}
+
+	return len(parts) == 0
 }
 
-// ParseRule returns a rule for the given pattern.
-func ParseRule(dir, pattern string) Rule {
-	pattern = strings.TrimSpace(pattern)
-	if pattern == "" {
-		return EmptyRule(pattern)
+// relative returns the path of name relative to dir.
+func relative(dir, name string) (string, bool) {
+	dir = strings.TrimSuffix(dir, "/")
+	if dir == "." {
+		return name, true
 	}
 
-	if strings.HasPrefix(pattern, "#") {
-		return EmptyRule(pattern)
+	if !strings.HasPrefix(name, dir+"/") {
+		return "", false
 	}
 
-	var rule Rule
-	if strings.Contains(pattern, "/") {
-		rule = PathRule(filepath.Join(dir, pattern))
-	} else {
-		rule = BaseRule(pattern)
+	return name[len(dir)+1:], true
+}
+
+// trimTrailingSpace removes trailing spaces that are not escaped with a backslash.
+func trimTrailingSpace(pattern string) string {
+	for strings.HasSuffix(pattern, " ") {
+		trimmed := pattern[:len(pattern)-1]
+		if strings.HasSuffix(trimmed, "\\") && !strings.HasSuffix(trimmed, "\\\\") {
+			break
+		}
+
+		pattern = trimmed
 	}
 
-	if strings.HasPrefix(pattern, "!") {
-		rule = NegateRule{rule}
+	return pattern
+}
+
+// convertClass rewrites negated character classes from [!...] to [^...].
+func convertClass(segment string) string {
+	var b strings.Builder
+	for i := 0; i < len(segment); i++ {
+		c := segment[i]
+		b.WriteByte(c)
+
+		switch {
+		case c == '\\' && i+1 < len(segment):
+			i++
+			b.WriteByte(segment[i])
+		case c == '[' && i+1 < len(segment) && segment[i+1] == '!':
+			i++
+			b.WriteByte('^')
+		}
 	}
 
-	return rule
+	return b.String()
 }
@@ -0,0 +1,123 @@
+package ignore
+
+import (
+	"testing"
+)
+
+// ruleCases are ported from the git ignore and wildmatch tests.
+var ruleCases = []struct {
+	pattern string
+	name    string
+	isDir   bool
+	match   bool
+}{
+	// basename patterns match at any depth
+	{"foo", "foo", false, true},
+	{"foo", "a/foo", false, true},
+	{"foo", "a/b/foo", true, true},
+	{"foo", "foobar", false, false},
+	{"*.o", "a/b.o", false, true},
+	{"*.o", "a/b.c", false, false},
+	{"?.c", "a.c", false, true},
+	{"?.c", "ab.c", false, false},
+	{"[abc].c", "b.c", false, true},
+	{"[!abc].c", "b.c", false, false},
+	{"[!abc].c", "d.c", false, true},
+	{"[^abc].c", "d.c", false, true},
+	{"[a-c]x", "bx", false, true},
+	{"[a-c]x", "dx", false, false},
+	// directory only patterns
+	{"build/", "build", true, true},
+	{"build/", "build", false, false},
+	{"build/", "src/build", true, true},
+	{"doc/frotz/", "doc/frotz", true, true},
+	{"doc/frotz/", "a/doc/frotz", true, false},
+	// slashes anchor the pattern
+	{"/foo", "foo", false, true},
+	{"/foo", "a/foo", false, false},
+	{"doc/*.html", "doc/index.html", false, true},
+	{"doc/*.html", "doc/api/index.html", false, false},
+	{"a/b", "x/a/b", false, false},
+	// double asterisks
+	{"**/foo", "foo", false, true},
+	{"**/foo", "a/b/foo", false, true},
+	{"**/foo/bar", "a/foo/bar", false, true},
+	{"**/foo/bar", "a/foo/baz", false, false},
+	{"abc/**", "abc/x", false, true},
+	{"abc/**", "abc/x/y", false, true},
+	{"abc/**", "abc", true, false},
+	{"a/**/b", "a/b", false, true},
+	{"a/**/b", "a/x/b", false, true},
+	{"a/**/b", "a/x/y/b", false, true},
+	{"a/**/b", "a/x/c", false, false},
+	{"a/**/b", "x/a/b", false, false},
+	{"foo**bar", "foobazbar", false, true},
+	{"foo**bar", "foo/bar", false, false},
+	// escaped characters
+	{"\\#foo", "#foo", false, true},
+	{"\\!foo", "!foo", false, true},
+	{"\\*", "*", false, true},
+	{"\\*", "x", false, false},
+	{"foo\\ ", "foo ", false, true},
+	{"foo  ", "foo", false, true},
+	{"\\[ab]", "[ab]", false, true},
+	{"\\[ab]", "a", false, false},
+}
+
+func TestRuleMatch(t *testing.T) {
+	for _, c := range ruleCases {
+		rule := ParseRule("", c.pattern)
+		if rule == nil {
+			t.Fatalf("failed to parse rule %q", c.pattern)
+		}
+
+		if match := rule.Match(c.name, c.isDir); match != c.match {
+			t.Errorf("pattern %q on %q: expected match %t", c.pattern, c.name, c.match)
+		}
+	}
+}
+
+func TestRuleDir(t *testing.T) {
+	rule := ParseRule("root/sub", "/foo/*.txt")
+
+	if !rule.Match("root/sub/foo/a.txt", false) {
+		t.Error("expected rule to match")
+	}
+
+	if rule.Match("root/foo/a.txt", false) {
+		t.Error("expected rule not to match")
+	}
+
+	if rule.Match("root/sub/x/foo/a.txt", false) {
+		t.Error("expected rule not to match")
+	}
+}
+
+func TestParseRuleEmpty(t *testing.T) {
+	for _, p := range []string{"", "   ", "# comment", "!", "/"} {
+		if rule := ParseRule("", p); rule != nil {
+			t.Errorf("expected pattern %q to be ignored", p)
+		}
+	}
+}
+
+func TestFilterNegate(t *testing.T) {
+	filter := New("", "*.log", "!important.log", "tmp/", "!tmp/")
+
+	if !filter.Match("debug.log", false) {
+		t.Error("expected ignore to match")
+	}
+
+	if filter.Match("a/important.log", false) {
+		t.Error("expected negated rule to re-include path")
+	}
+
+	if filter.Match("tmp", true) {
+		t.Error("expected last matching rule to win")
+	}
+
+	other := New("", "important.log")
+	if !filter.Merge(other).Match("important.log", false) {
+		t.Error("expected merged rule to take precedence")
+	}
+}
@@ -8,10 +8,12 @@ import (
 	connmgr "github.com/libp2p/go-libp2p-connmgr"
 	"github.com/libp2p/go-libp2p-core/crypto"
 	"github.com/libp2p/go-libp2p-core/host"
+	"github.com/libp2p/go-libp2p-core/peer"
 	"github.com/libp2p/go-libp2p-core/routing"
 	"github.com/libp2p/go-libp2p-kad-dht/dual"
 	noise "github.com/libp2p/go-libp2p-noise"
 	libp2ptls "github.com/libp2p/go-libp2p-tls"
+	ma "github.com/multiformats/go-multiaddr"
 )
 
 const (
@@ -50,3 +52,50 @@ func NewHost(ctx context.Context, priv crypto.PrivKey, listenAddr []string) (hos
 
 	return host, router, err
 }
+
+// NewClientHost returns a libp2p host that only makes outgoing connections.
+func NewClientHost(ctx context.Context) (host.Host, error) {
+	return libp2p.New(ctx,
+		libp2p.NoListenAddrs,
+		libp2p.Security(libp2ptls.ID, libp2ptls.New),
+		libp2p.Security(noise.ID, noise.New),
+		libp2p.DefaultTransports,
+	)
+}
+
+// LocalAddrInfo returns the addresses used to dial a peer on this machine.
+//
+// Unspecified listen addresses are replaced with the loopback address.
+func LocalAddrInfo(id peer.ID, listenAddrs []string) (peer.AddrInfo, error) {
+	info := peer.AddrInfo{ID: id}
+
+	for _, addr := range listenAddrs {
+		maddr, err := ma.NewMultiaddr(addr)
+		if err != nil {
+			return info, err
+		}
+
+		first, rest := ma.SplitFirst(maddr)
+		if first == nil {
+			continue
+		}
+
+		switch {
+		case first.Protocol().Code == ma.P_IP4 && first.Value() == "0.0.0.0":
+			maddr = ma.StringCast("/ip4/127.0.0.1")
+		case first.Protocol().Code == ma.P_IP6 && first.Value() == "::":
+			maddr = ma.StringCast("/ip6/::1")
+		default:
+			info.Addrs = append(info.Addrs, maddr)
+			continue
+		}
+
+		if rest != nil {
+			maddr = maddr.Encapsulate(rest)
+		}
+
+		info.Addrs = append(info.Addrs, maddr)
+	}
+
+	return info, nil
+}
@@ -7,8 +7,10 @@ import (
 	bitswap "github.com/ipfs/go-bitswap"
 	bsnet "github.com/ipfs/go-bitswap/network"
 	blockservice "github.com/ipfs/go-blockservice"
+	cid "github.com/ipfs/go-cid"
 	datastore "github.com/ipfs/go-datastore"
 	blockstore "github.com/ipfs/go-ipfs-blockstore"
+	offline "github.com/ipfs/go-ipfs-exchange-offline"
 	provider "github.com/ipfs/go-ipfs-provider"
 	"github.com/ipfs/go-ipfs-provider/queue"
 	"github.com/ipfs/go-ipfs-provider/simple"
@@ -25,12 +27,19 @@ const (
 	QueueName = "repro"
 )
 
+// RootsFunc returns the CIDs of all dags that must be kept.
+type RootsFunc func(ctx context.Context, ds ipld.DAGService) ([]cid.Cid, error)
+
 // Peer implements p2p services.
 type Peer struct {
 	// Blocks is the ipfs blockstore.
-	Blocks blockstore.Blockstore
+	//
+	// Hold a pin lock while adding objects that are not yet pinned.
+	Blocks blockstore.GCBlockstore
 	// DAG implements ipld DAGService.
 	DAG ipld.DAGService
+	// Local is a DAGService that never fetches blocks from the network.
+	Local ipld.DAGService
 	// Host is the libp2p host.
 	Host host.Host
 	// Router is the libp2p router.
@@ -38,8 +47,11 @@ type Peer struct {
 }
 
 // New returns a new peer using the given host, router, and datstore.
-func NewPeer(ctx context.Context, host host.Host, router routing.Routing, dstore datastore.Batching) (*Peer, error) {
-	bstore := blockstore.NewBlockstore(dstore)
+//
+// The roots func returns the pinned dags that are periodically reprovided.
+func NewPeer(ctx context.Context, host host.Host, router routing.Routing, dstore datastore.Batching, roots RootsFunc) (*Peer, error) {
+	bstore := blockstore.NewGCBlockstore(blockstore.NewBlockstore(dstore), blockstore.NewGCLocker())
+	local := merkledag.NewDAGService(blockservice.New(bstore, offline.Exchange(bstore)))
 	net := bsnet.NewFromIpfsHost(host, router)
 	exc := bitswap.New(ctx, net, bstore)
 	bserv := blockservice.New(bstore, exc)
@@ -50,8 +62,11 @@ func NewPeer(ctx context.Context, host host.Host, router routing.Routing, dstore
 	}
 
 	prov := simple.NewProvider(ctx, queue, router)
-	bspr := simple.NewBlockstoreProvider(bstore)
-	repr := simple.NewReprovider(ctx, ReprovideInterval, router, bspr)
+	keys := func(ctx context.Context) (<-chan cid.Cid, error) {
+		return pinnedKeys(ctx, bstore, local, roots)
+	}
+
+	repr := simple.NewReprovider(ctx, ReprovideInterval, router, keys)
 
 	sys := provider.NewSystem(prov, repr)
 	sys.Run()
@@ -64,10 +79,53 @@ func NewPeer(ctx context.Context, host host.Host, router routing.Routing, dstore
 		return nil, err
 	}
 
+	dag := merkledag.NewDAGService(bserv)
+	ServeSync(host, local)
+
 	return &Peer{
 		Blocks: bstore,
-		DAG:    merkledag.NewDAGService(bserv),
+		DAG:    dag,
+		Local:  local,
 		Host:   host,
 		Router: router,
 	}, nil
 }
+
+// pinnedKeys returns the CIDs of all blocks reachable from the pinned roots.
+func pinnedKeys(ctx context.Context, bstore blockstore.GCBlockstore, ds ipld.DAGService, roots RootsFunc) (<-chan cid.Cid, error) {
+	// roots may change while objects are being added
+	unlocker := bstore.GCLock()
+	ids, err := roots(ctx, ds)
+	unlocker.Unlock()
+
+	if err != nil {
+		return nil, err
+	}
+
+	set := cid.NewSet()
+	out := make(chan cid.Cid)
+
+	visit := func(id cid.Cid) bool {
+		if !set.Visit(id) {
+			return false
+		}
+
+		select {
+		case out <- id:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	go func() {
+		defer close(out)
+
+		// a root with missing blocks does not stop the others from being provided
+		for _, id := range ids {
+			merkledag.Walk(ctx, merkledag.GetLinksWithDAG(ds), id, visit)
+		}
+	}()
+
+	return out, nil
+}
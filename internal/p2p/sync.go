@@ -0,0 +1,127 @@
+package p2p
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	cid "github.com/ipfs/go-cid"
+	blockstore "github.com/ipfs/go-ipfs-blockstore"
+	ipld "github.com/ipfs/go-ipld-format"
+	"github.com/ipld/go-car/util"
+	"github.com/libp2p/go-libp2p-core/host"
+	"github.com/libp2p/go-libp2p-core/network"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/libp2p/go-libp2p-core/protocol"
+
+	"github.com/multiverse-vcs/go-multiverse/pkg/dag"
+)
+
+const (
+	// SyncProtocol is the protocol ID for exchanging dags between peers.
+	SyncProtocol = protocol.ID("/multiverse/sync/1.0")
+	// SyncTimeout is the maximum time to serve a sync request.
+	SyncTimeout = 10 * time.Minute
+)
+
+// syncRequest is sent by the peer that is fetching blocks.
+type syncRequest struct {
+	// Want contains the roots of the dags to fetch.
+	Want []cid.Cid `json:"want"`
+	// Have contains roots the requester already has.
+	Have []cid.Cid `json:"have"`
+}
+
+// ServeSync streams blocks from the dag to peers that request them.
+//
+// The dag should only read local blocks so that peers cannot make
+// the server fetch arbitrary content from the network.
+//
+// Blocks are written as a car one at a time so neither peer has to
+// buffer the entire dag.
+func ServeSync(h host.Host, ds ipld.NodeGetter) {
+	h.SetStreamHandler(SyncProtocol, func(s network.Stream) {
+		if err := serveSync(s, ds); err != nil {
+			s.Reset()
+			return
+		}
+
+		s.Close()
+	})
+}
+
+// serveSync reads a request from the stream and writes the wanted blocks.
+func serveSync(s network.Stream, ds ipld.NodeGetter) error {
+	ctx, cancel := context.WithTimeout(context.Background(), SyncTimeout)
+	defer cancel()
+
+	// slow or stalled peers must not hold the stream open forever
+	// but the deadline is best effort because not all transports support it
+	s.SetDeadline(time.Now().Add(SyncTimeout))
+
+	data, err := util.LdRead(bufio.NewReader(s))
+	if err != nil {
+		return err
+	}
+
+	var req syncRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		return err
+	}
+
+	refs := cid.NewSet()
+	for _, id := range req.Have {
+		refs.Add(id)
+	}
+
+	return dag.WriteCar(ctx, ds, req.Want, refs, s, nil)
+}
+
+// Fetch requests the dags reachable from want but not from have and adds them to the blockstore.
+func Fetch(ctx context.Context, h host.Host, id peer.ID, bs blockstore.Blockstore, want, have []cid.Cid) error {
+	s, err := h.NewStream(ctx, id, SyncProtocol)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	req := syncRequest{
+		Want: want,
+		Have: have,
+	}
+
+	data, err := json.Marshal(&req)
+	if err != nil {
+		return err
+	}
+
+	if err := util.LdWrite(s, data); err != nil {
+		s.Reset()
+		return err
+	}
+
+	if err := s.CloseWrite(); err != nil {
+		s.Reset()
+		return err
+	}
+
+	if _, err := dag.ReadCar(ctx, bs, s, nil); err != nil {
+		s.Reset()
+		return err
+	}
+
+	for _, id := range want {
+		has, err := bs.Has(id)
+		if err != nil {
+			return err
+		}
+
+		if !has {
+			return errors.New("sync did not return all wanted blocks")
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package p2p
+
+import (
+	"context"
+	"testing"
+
+	cid "github.com/ipfs/go-cid"
+	datastore "github.com/ipfs/go-datastore"
+	dssync "github.com/ipfs/go-datastore/sync"
+	blockstore "github.com/ipfs/go-ipfs-blockstore"
+	ipld "github.com/ipfs/go-ipld-format"
+	merkledag "github.com/ipfs/go-merkledag"
+	"github.com/ipfs/go-merkledag/dagutils"
+	mocknet "github.com/libp2p/go-libp2p/p2p/net/mock"
+)
+
+func TestSync(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mn, err := mocknet.FullMeshConnected(ctx, 2)
+	if err != nil {
+		t.Fatal("failed to create hosts")
+	}
+
+	hosts := mn.Hosts()
+	ds := dagutils.NewMemoryDagService()
+
+	have := merkledag.NodeWithData([]byte("have"))
+	want := merkledag.NodeWithData([]byte("want"))
+
+	if err := want.AddNodeLink("have", have); err != nil {
+		t.Fatal("failed to add link")
+	}
+
+	if err := ds.AddMany(ctx, []ipld.Node{have, want}); err != nil {
+		t.Fatal("failed to add nodes")
+	}
+
+	ServeSync(hosts[0], ds)
+
+	bs := blockstore.NewBlockstore(dssync.MutexWrap(datastore.NewMapDatastore()))
+	if err := Fetch(ctx, hosts[1], hosts[0].ID(), bs, []cid.Cid{want.Cid()}, []cid.Cid{have.Cid()}); err != nil {
+		t.Fatalf("failed to fetch: %s", err)
+	}
+
+	if ok, _ := bs.Has(want.Cid()); !ok {
+		t.Error("expected wanted block to be fetched")
+	}
+
+	if ok, _ := bs.Has(have.Cid()); ok {
+		t.Error("expected known block to be skipped")
+	}
+
+	missing := merkledag.NodeWithData([]byte("missing"))
+	if err := Fetch(ctx, hosts[1], hosts[0].ID(), bs, []cid.Cid{missing.Cid()}, nil); err == nil {
+		t.Error("expected missing block to fail")
+	}
+}
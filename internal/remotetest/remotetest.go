@@ -0,0 +1,219 @@
+// Package remotetest creates remote servers connected by a mock network for tests.
+package remotetest
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	bitswap "github.com/ipfs/go-bitswap"
+	bsnet "github.com/ipfs/go-bitswap/network"
+	blockservice "github.com/ipfs/go-blockservice"
+	cid "github.com/ipfs/go-cid"
+	datastore "github.com/ipfs/go-datastore"
+	dssync "github.com/ipfs/go-datastore/sync"
+	blockstore "github.com/ipfs/go-ipfs-blockstore"
+	offline "github.com/ipfs/go-ipfs-exchange-offline"
+	merkledag "github.com/ipfs/go-merkledag"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/libp2p/go-libp2p-core/routing"
+	mocknet "github.com/libp2p/go-libp2p/p2p/net/mock"
+	ma "github.com/multiformats/go-multiaddr"
+
+	"github.com/multiverse-vcs/go-multiverse/internal/p2p"
+	"github.com/multiverse-vcs/go-multiverse/pkg/name"
+	"github.com/multiverse-vcs/go-multiverse/pkg/object"
+	"github.com/multiverse-vcs/go-multiverse/pkg/remote"
+)
+
+// Routing is a router shared by all peers of a mock network.
+//
+// Values are stored in memory and providers are never found so
+// blocks are only exchanged between connected peers.
+type Routing struct {
+	mu     sync.Mutex
+	values map[string][]byte
+}
+
+// NewRouting returns an empty router.
+func NewRouting() *Routing {
+	return &Routing{
+		values: make(map[string][]byte),
+	}
+}
+
+// Provide does nothing.
+func (r *Routing) Provide(ctx context.Context, id cid.Cid, announce bool) error {
+	return nil
+}
+
+// FindProvidersAsync returns a closed channel.
+func (r *Routing) FindProvidersAsync(ctx context.Context, id cid.Cid, count int) <-chan peer.AddrInfo {
+	out := make(chan peer.AddrInfo)
+	close(out)
+	return out
+}
+
+// FindPeer always fails because peers are connected by the mock network.
+func (r *Routing) FindPeer(ctx context.Context, id peer.ID) (peer.AddrInfo, error) {
+	return peer.AddrInfo{}, routing.ErrNotFound
+}
+
+// PutValue stores the value.
+func (r *Routing) PutValue(ctx context.Context, key string, val []byte, opts ...routing.Option) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.values[key] = val
+	return nil
+}
+
+// GetValue returns the stored value.
+func (r *Routing) GetValue(ctx context.Context, key string, opts ...routing.Option) ([]byte, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	val, ok := r.values[key]
+	if !ok {
+		return nil, routing.ErrNotFound
+	}
+
+	return val, nil
+}
+
+// SearchValue waits until the value is stored or the context is done.
+func (r *Routing) SearchValue(ctx context.Context, key string, opts ...routing.Option) (<-chan []byte, error) {
+	out := make(chan []byte, 1)
+
+	go func() {
+		defer close(out)
+
+		ticker := time.NewTicker(10 * time.Millisecond)
+		defer ticker.Stop()
+
+		for {
+			if val, err := r.GetValue(ctx, key); err == nil {
+				out <- val
+				return
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return out, nil
+}
+
+// Bootstrap does nothing.
+func (r *Routing) Bootstrap(ctx context.Context) error {
+	return nil
+}
+
+// NewNetwork returns n connected remote servers with configs in subdirectories of dir.
+func NewNetwork(ctx context.Context, dir string, n int) (mocknet.Mocknet, []*remote.Server, error) {
+	mn := mocknet.New(ctx)
+	router := NewRouting()
+
+	var servers []*remote.Server
+	for i := 0; i < n; i++ {
+		root := filepath.Join(dir, fmt.Sprintf("peer%d", i))
+		if err := os.MkdirAll(root, 0755); err != nil {
+			return nil, nil, err
+		}
+
+		addr := ma.StringCast(fmt.Sprintf("/ip4/127.0.0.1/tcp/%d", 4000+i))
+		server, err := newServer(ctx, mn, router, root, addr)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		servers = append(servers, server)
+	}
+
+	if err := mn.LinkAll(); err != nil {
+		return nil, nil, err
+	}
+
+	if err := mn.ConnectAllButSelf(); err != nil {
+		return nil, nil, err
+	}
+
+	return mn, servers, nil
+}
+
+// newServer returns a remote server on the mock network.
+func newServer(ctx context.Context, mn mocknet.Mocknet, router *Routing, root string, addr ma.Multiaddr) (*remote.Server, error) {
+	key, err := p2p.GenerateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	enc, err := p2p.EncodeKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	host, err := mn.AddPeer(key, addr)
+	if err != nil {
+		return nil, err
+	}
+
+	dstore := dssync.MutexWrap(datastore.NewMapDatastore())
+	bstore := blockstore.NewGCBlockstore(blockstore.NewBlockstore(dstore), blockstore.NewGCLocker())
+	exc := bitswap.New(ctx, bsnet.NewFromIpfsHost(host, router), bstore)
+	local := merkledag.NewDAGService(blockservice.New(bstore, offline.Exchange(bstore)))
+
+	p2p.ServeSync(host, local)
+
+	namesys, err := name.NewSystem(ctx, host, router, router, dstore)
+	if err != nil {
+		return nil, err
+	}
+
+	config := remote.NewConfig(root)
+	config.PrivateKey = enc
+
+	peer := &p2p.Peer{
+		Blocks: bstore,
+		DAG:    merkledag.NewDAGService(blockservice.New(bstore, exc)),
+		Local:  local,
+		Host:   host,
+		Router: router,
+	}
+
+	return &remote.Server{
+		Config:  config,
+		Peer:    peer,
+		Namesys: namesys,
+		Root:    root,
+	}, nil
+}
+
+// AddRepository adds the repository to the server author and publishes the author.
+func AddRepository(ctx context.Context, server *remote.Server, rname string, repo *object.Repository) error {
+	key, err := p2p.DecodeKey(server.Config.PrivateKey)
+	if err != nil {
+		return err
+	}
+
+	repoID, err := object.AddRepository(ctx, server.Peer.DAG, repo)
+	if err != nil {
+		return err
+	}
+
+	server.Config.Author.Repositories[rname] = repoID
+
+	authorID, err := object.AddAuthor(ctx, server.Peer.DAG, server.Config.Author)
+	if err != nil {
+		return err
+	}
+
+	return server.Namesys.Publish(ctx, key, authorID)
+}
@@ -0,0 +1,74 @@
+package author
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/multiverse-vcs/go-multiverse/pkg/rpc"
+	"github.com/multiverse-vcs/go-multiverse/pkg/rpc/author"
+	"github.com/urfave/cli/v2"
+)
+
+// NewAliasCommand returns a new command.
+func NewAliasCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "alias",
+		Usage:     "List, create, or delete author aliases",
+		ArgsUsage: "[<name> [peer-id]]",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:    "delete",
+				Aliases: []string{"d"},
+				Usage:   "Delete the alias",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			args := author.AliasArgs{
+				Name:   c.Args().Get(0),
+				Delete: c.Bool("delete"),
+			}
+
+			switch {
+			case c.NArg() == 0 && !args.Delete:
+			case c.NArg() == 1 && args.Delete:
+			case c.NArg() == 2 && !args.Delete:
+				peerID, err := peer.Decode(c.Args().Get(1))
+				if err != nil {
+					return err
+				}
+
+				args.PeerID = peerID
+			default:
+				cli.ShowSubcommandHelpAndExit(c, 1)
+			}
+
+			client, err := rpc.NewClient()
+			if err != nil {
+				return cli.Exit(rpc.DialErrMsg, -1)
+			}
+
+			var reply author.AliasReply
+			if err := client.Call("Author.Alias", &args, &reply); err != nil {
+				return err
+			}
+
+			if c.NArg() != 0 {
+				return nil
+			}
+
+			var names []string
+			for name := range reply.Aliases {
+				names = append(names, name)
+			}
+
+			sort.Strings(names)
+
+			for _, name := range names {
+				fmt.Printf("%-32s%s\n", name, reply.Aliases[name].Pretty())
+			}
+
+			return nil
+		},
+	}
+}
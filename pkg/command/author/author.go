@@ -11,10 +11,12 @@ func NewCommand() *cli.Command {
 		Usage: "Manage author profiles",
 		Subcommands: []*cli.Command{
 			NewSelfCommand(),
+			NewSetCommand(),
 			NewListCommand(),
 			NewViewCommand(),
 			NewFollowCommand(),
 			NewUnfollowCommand(),
+			NewAliasCommand(),
 		},
 	}
 }
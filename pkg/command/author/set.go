@@ -0,0 +1,38 @@
+package author
+
+import (
+	"github.com/multiverse-vcs/go-multiverse/pkg/rpc"
+	"github.com/multiverse-vcs/go-multiverse/pkg/rpc/author"
+	"github.com/urfave/cli/v2"
+)
+
+// NewSetCommand returns a new command.
+func NewSetCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "set",
+		Usage:     "Set your profile name or email",
+		ArgsUsage: "<name|email> <value>",
+		Action: func(c *cli.Context) error {
+			if c.NArg() != 2 {
+				cli.ShowSubcommandHelpAndExit(c, 1)
+			}
+
+			client, err := rpc.NewClient()
+			if err != nil {
+				return cli.Exit(rpc.DialErrMsg, -1)
+			}
+
+			args := author.SetArgs{
+				Key:   c.Args().Get(0),
+				Value: c.Args().Get(1),
+			}
+
+			var reply author.SetReply
+			if err := client.Call("Author.Set", &args, &reply); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+}
@@ -0,0 +1,17 @@
+package bundle
+
+import (
+	"github.com/urfave/cli/v2"
+)
+
+// NewCommand returns a new command.
+func NewCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "bundle",
+		Usage: "Move history offline with bundle files",
+		Subcommands: []*cli.Command{
+			NewCreateCommand(),
+			NewUnbundleCommand(),
+		},
+	}
+}
@@ -0,0 +1,126 @@
+package bundle
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	cid "github.com/ipfs/go-cid"
+	"github.com/urfave/cli/v2"
+
+	"github.com/multiverse-vcs/go-multiverse/pkg/command/context"
+	"github.com/multiverse-vcs/go-multiverse/pkg/dag"
+	"github.com/multiverse-vcs/go-multiverse/pkg/object"
+)
+
+// NewCreateCommand returns a new command.
+func NewCreateCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "create",
+		Usage:     "Write branches and tags to a bundle file",
+		ArgsUsage: "<file>",
+		Flags: []cli.Flag{
+			&cli.StringSliceFlag{
+				Name:    "branch",
+				Aliases: []string{"b"},
+				Usage:   "Branch to include (defaults to all branches)",
+			},
+			&cli.BoolFlag{
+				Name:  "tags",
+				Usage: "Include all local tags",
+			},
+			&cli.StringSliceFlag{
+				Name:  "since",
+				Usage: "Exclude history the receiver already has at this branch or commit",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			if c.NArg() != 1 {
+				cli.ShowSubcommandHelpAndExit(c, 1)
+			}
+
+			cwd, err := os.Getwd()
+			if err != nil {
+				return err
+			}
+
+			cc, err := context.New(cwd)
+			if err != nil {
+				return err
+			}
+
+			bundle := object.NewBundle()
+
+			names := c.StringSlice("branch")
+			if len(names) == 0 {
+				for name := range cc.Config.Branches {
+					names = append(names, name)
+				}
+			}
+
+			for _, name := range names {
+				branch, ok := cc.Config.Branches[name]
+				if !ok {
+					return fmt.Errorf("branch %s does not exist", name)
+				}
+
+				if branch.Head.Defined() {
+					bundle.Branches[name] = branch.Head
+				}
+			}
+
+			if c.Bool("tags") {
+				for name, id := range cc.Config.Tags {
+					bundle.Tags[name] = id
+				}
+			}
+
+			if len(bundle.Branches) == 0 && len(bundle.Tags) == 0 {
+				return errors.New("nothing to bundle")
+			}
+
+			refs := cid.NewSet()
+			for _, ref := range c.StringSlice("since") {
+				id, err := cc.Config.Ref(ref)
+				if err != nil {
+					return err
+				}
+
+				if _, err := object.GetCommit(c.Context, cc.DAG, id); err != nil {
+					return err
+				}
+
+				if refs.Visit(id) {
+					bundle.Prerequisites = append(bundle.Prerequisites, id)
+				}
+			}
+
+			id, err := object.AddBundle(c.Context, cc.DAG, bundle)
+			if err != nil {
+				return err
+			}
+
+			file, err := os.Create(c.Args().Get(0))
+			if err != nil {
+				return err
+			}
+			defer file.Close()
+
+			var count int
+			progress := func(blocks int, size int64) {
+				count = blocks
+			}
+
+			if err := dag.WriteCar(c.Context, cc.DAG, []cid.Cid{id}, refs, file, progress); err != nil {
+				return err
+			}
+
+			if err := file.Close(); err != nil {
+				return err
+			}
+
+			fmt.Printf("bundled %d objects\n", count)
+			return nil
+		},
+	}
+}
@@ -0,0 +1,215 @@
+package bundle
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	cid "github.com/ipfs/go-cid"
+	car "github.com/ipld/go-car"
+	"github.com/urfave/cli/v2"
+
+	"github.com/multiverse-vcs/go-multiverse/pkg/command/context"
+	"github.com/multiverse-vcs/go-multiverse/pkg/dag"
+	"github.com/multiverse-vcs/go-multiverse/pkg/merge"
+	"github.com/multiverse-vcs/go-multiverse/pkg/object"
+)
+
+// NewUnbundleCommand returns a new command.
+func NewUnbundleCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "unbundle",
+		Usage:     "Import branches and tags from a bundle file",
+		ArgsUsage: "<file>",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:    "force",
+				Aliases: []string{"f"},
+				Usage:   "Replace diverged branches and existing tags",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			if c.NArg() != 1 {
+				cli.ShowSubcommandHelpAndExit(c, 1)
+			}
+
+			cwd, err := os.Getwd()
+			if err != nil {
+				return err
+			}
+
+			cc, err := context.New(cwd)
+			if err != nil {
+				return err
+			}
+
+			file, err := os.Open(c.Args().Get(0))
+			if err != nil {
+				return err
+			}
+			defer file.Close()
+
+			bundle, err := readManifest(file)
+			if err != nil {
+				return err
+			}
+
+			for _, id := range bundle.Prerequisites {
+				has, err := cc.Blocks.Has(id)
+				if err != nil {
+					return err
+				}
+
+				if !has {
+					return fmt.Errorf("missing prerequisite %s", id.String())
+				}
+			}
+
+			if _, err := file.Seek(0, io.SeekStart); err != nil {
+				return err
+			}
+
+			if _, err := dag.ReadCar(c.Context, cc.Blocks, file, nil); err != nil {
+				return err
+			}
+
+			if err := updateBranches(c, cc, bundle.Branches); err != nil {
+				return err
+			}
+
+			updateTags(c, cc, bundle.Tags)
+			return cc.Config.Write()
+		},
+	}
+}
+
+// readManifest returns the bundle manifest from the first block of the car.
+func readManifest(r io.Reader) (*object.Bundle, error) {
+	cr, err := car.NewCarReader(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(cr.Header.Roots) != 1 {
+		return nil, errors.New("invalid bundle roots")
+	}
+
+	block, err := cr.Next()
+	if err != nil {
+		return nil, err
+	}
+
+	if block.Cid() != cr.Header.Roots[0] {
+		return nil, errors.New("invalid bundle manifest")
+	}
+
+	return object.BundleFromCBOR(block.RawData())
+}
+
+// updateBranches creates missing branches and fast forwards existing branches.
+func updateBranches(c *cli.Context, cc *context.Context, heads map[string]cid.Cid) error {
+	var names []string
+	for name := range heads {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		id := heads[name]
+
+		commit, err := object.GetCommit(c.Context, cc.DAG, id)
+		if err != nil {
+			return err
+		}
+
+		branch, ok := cc.Config.Branches[name]
+		if !ok {
+			cc.Config.Branches[name] = &context.Branch{
+				Head:  id,
+				Stash: commit.Tree,
+			}
+
+			fmt.Printf("new branch %s\n", name)
+			continue
+		}
+
+		if branch.Head == id {
+			continue
+		}
+
+		if name == cc.Config.Branch {
+			fmt.Printf("skipped current branch %s (merge %s)\n", name, id.String())
+			continue
+		}
+
+		base, err := merge.Base(c.Context, cc.DAG, branch.Head, id)
+		if err != nil {
+			return err
+		}
+
+		if base != branch.Head && !c.Bool("force") {
+			fmt.Printf("rejected branch %s (non fast forward)\n", name)
+			continue
+		}
+
+		clean, err := isClean(c, cc, branch)
+		if err != nil {
+			return err
+		}
+
+		if !clean {
+			fmt.Printf("rejected branch %s (uncommitted changes)\n", name)
+			continue
+		}
+
+		branch.Head = id
+		branch.Stash = commit.Tree
+		fmt.Printf("updated branch %s\n", name)
+	}
+
+	return nil
+}
+
+// isClean returns true if the branch stash matches the tree of its head.
+func isClean(c *cli.Context, cc *context.Context, branch *context.Branch) (bool, error) {
+	if !branch.Head.Defined() {
+		return true, nil
+	}
+
+	commit, err := object.GetCommit(c.Context, cc.DAG, branch.Head)
+	if err != nil {
+		return false, err
+	}
+
+	return branch.Stash == commit.Tree, nil
+}
+
+// updateTags adds missing tags and replaces existing tags when forced.
+func updateTags(c *cli.Context, cc *context.Context, tags map[string]cid.Cid) {
+	var names []string
+	for name := range tags {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		id := tags[name]
+
+		prev, ok := cc.Config.Tags[name]
+		if ok && prev == id {
+			continue
+		}
+
+		if ok && !c.Bool("force") {
+			fmt.Printf("rejected tag %s (already exists)\n", name)
+			continue
+		}
+
+		cc.Config.Tags[name] = id
+		fmt.Printf("new tag %s\n", name)
+	}
+}
@@ -0,0 +1,114 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/multiverse-vcs/go-multiverse/internal/ignore"
+	"github.com/multiverse-vcs/go-multiverse/pkg/command/context"
+)
+
+// NewCheckIgnoreCommand returns a new cli command.
+func NewCheckIgnoreCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "check-ignore",
+		Usage:     "Print the ignore rules matching paths",
+		ArgsUsage: "<path>...",
+		Action: func(c *cli.Context) error {
+			if c.NArg() < 1 {
+				cli.ShowAppHelpAndExit(c, -1)
+			}
+
+			cwd, err := os.Getwd()
+			if err != nil {
+				return err
+			}
+
+			cc, err := context.New(cwd)
+			if err != nil {
+				return err
+			}
+
+			for _, arg := range c.Args().Slice() {
+				path, err := filepath.Abs(arg)
+				if err != nil {
+					return err
+				}
+
+				rule, err := checkIgnore(cc, path, strings.HasSuffix(arg, "/"))
+				if err != nil {
+					return err
+				}
+
+				if rule == nil || rule.Negate {
+					continue
+				}
+
+				fmt.Printf("%s:%d:%s\t%s\n", ignoreSource(cc, rule), rule.Line, rule.Pattern, arg)
+			}
+
+			return nil
+		},
+	}
+}
+
+// ignoreSource returns the name of the file the rule was loaded from.
+func ignoreSource(cc *context.Context, rule *ignore.Rule) string {
+	if rule.Source == "" {
+		return "<default>"
+	}
+
+	rel, err := filepath.Rel(cc.Root, rule.Source)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return rule.Source
+	}
+
+	return rel
+}
+
+// checkIgnore returns the rule that causes the path to be ignored.
+//
+// Each parent directory is checked with the rules from every ignore file
+// above it so that paths inside ignored directories are also reported.
+func checkIgnore(cc *context.Context, path string, isDir bool) (*ignore.Rule, error) {
+	rel, err := filepath.Rel(cc.Root, path)
+	if err != nil {
+		return nil, err
+	}
+
+	if rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return nil, errors.New("path is outside repository")
+	}
+
+	filter := cc.Ignore
+	dir := cc.Root
+
+	parts := strings.Split(rel, string(filepath.Separator))
+	for i, part := range parts {
+		other, err := ignore.Load(dir)
+		if err != nil {
+			return nil, err
+		}
+
+		filter = filter.Merge(other)
+		dir = filepath.Join(dir, part)
+
+		last := i == len(parts)-1
+		if last && !isDir {
+			info, err := os.Lstat(dir)
+			isDir = err == nil && info.IsDir()
+		}
+
+		rule := filter.Find(dir, isDir || !last)
+		if last || (rule != nil && !rule.Negate) {
+			return rule, nil
+		}
+	}
+
+	return nil, nil
+}
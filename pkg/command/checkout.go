@@ -2,9 +2,11 @@ package command
 
 import (
 	"errors"
+	"fmt"
 	"os"
 
 	cid "github.com/ipfs/go-cid"
+	ipld "github.com/ipfs/go-ipld-format"
 	"github.com/urfave/cli/v2"
 
 	"github.com/multiverse-vcs/go-multiverse/pkg/command/context"
@@ -33,6 +35,11 @@ func NewCheckoutCommand() *cli.Command {
 				Name:  "head",
 				Usage: "Checkout branch head",
 			},
+			&cli.BoolFlag{
+				Name:    "dry-run",
+				Aliases: []string{"n"},
+				Usage:   "Print files that would be removed",
+			},
 		},
 		Action: func(c *cli.Context) error {
 			cwd, err := os.Getwd()
@@ -72,7 +79,7 @@ func NewCheckoutCommand() *cli.Command {
 				cli.ShowAppHelpAndExit(c, -1)
 			}
 
-			stash, err := fs.Add(c.Context, cc.DAG, cc.Root, context.DefaultIgnore)
+			stash, err := addTree(c, cc)
 			if err != nil {
 				return err
 			}
@@ -86,17 +93,40 @@ func NewCheckoutCommand() *cli.Command {
 				return errors.New("uncommitted changes")
 			}
 
+			if cc.Config.Merge != nil && !c.IsSet("force") {
+				return errors.New("merge in progress")
+			}
+
 			tree, err := cc.DAG.Get(c.Context, treeID)
 			if err != nil {
 				return err
 			}
 
-			if err := fs.Write(c.Context, cc.DAG, cc.Root, tree); err != nil {
+			if c.IsSet("dry-run") {
+				return printPrune(c, cc, tree)
+			}
+
+			if err := fs.Checkout(c.Context, cc.DAG, cc.Root, tree, cc.Ignore); err != nil {
 				return err
 			}
 
 			branch.Stash = treeID
+			cc.Config.Merge = nil
 			return cc.Config.Write()
 		},
 	}
 }
+
+// printPrune prints the files that would be removed by checking out the tree.
+func printPrune(c *cli.Context, cc *context.Context, tree ipld.Node) error {
+	removed, err := fs.Prune(c.Context, cc.DAG, cc.Root, tree, cc.Ignore, true)
+	if err != nil {
+		return err
+	}
+
+	for _, p := range removed {
+		fmt.Printf("would remove %s\n", p)
+	}
+
+	return nil
+}
@@ -5,8 +5,10 @@ import (
 
 	"github.com/multiverse-vcs/go-multiverse/pkg/command/author"
 	"github.com/multiverse-vcs/go-multiverse/pkg/command/branch"
+	"github.com/multiverse-vcs/go-multiverse/pkg/command/bundle"
 	"github.com/multiverse-vcs/go-multiverse/pkg/command/remote"
 	"github.com/multiverse-vcs/go-multiverse/pkg/command/repo"
+	"github.com/multiverse-vcs/go-multiverse/pkg/command/tag"
 	"github.com/urfave/cli/v2"
 )
 
@@ -28,9 +30,16 @@ func NewApp() *cli.App {
 			NewSwitchCommand(),
 			NewPushCommand(),
 			NewPullCommand(),
+			NewMergeCommand(),
 			NewStatusCommand(),
+			NewDiffCommand(),
 			NewLogCommand(),
+			NewGCCommand(),
+			NewFsckCommand(),
+			NewCheckIgnoreCommand(),
 			branch.NewCommand(),
+			bundle.NewCommand(),
+			tag.NewCommand(),
 			remote.NewCommand(),
 			repo.NewCommand(),
 			author.NewCommand(),
@@ -8,7 +8,6 @@ import (
 
 	"github.com/multiverse-vcs/go-multiverse/pkg/command/context"
 	"github.com/multiverse-vcs/go-multiverse/pkg/dag"
-	"github.com/multiverse-vcs/go-multiverse/pkg/fs"
 	"github.com/multiverse-vcs/go-multiverse/pkg/object"
 )
 
@@ -23,6 +22,11 @@ func NewCommitCommand() *cli.Command {
 				Aliases: []string{"m"},
 				Usage:   "Description of the changes",
 			},
+			&cli.BoolFlag{
+				Name:    "sign",
+				Aliases: []string{"s"},
+				Usage:   "Sign the commit with the daemon key",
+			},
 		},
 		Action: func(c *cli.Context) error {
 			cwd, err := os.Getwd()
@@ -35,7 +39,7 @@ func NewCommitCommand() *cli.Command {
 				return err
 			}
 
-			tree, err := fs.Add(c.Context, cc.DAG, cc.Root, context.DefaultIgnore)
+			tree, err := addTree(c, cc)
 			if err != nil {
 				return err
 			}
@@ -46,7 +50,7 @@ func NewCommitCommand() *cli.Command {
 				return err
 			}
 
-			if len(diffs) == 0 {
+			if len(diffs) == 0 && cc.Config.Merge == nil {
 				return errors.New("no changes to commit")
 			}
 
@@ -58,6 +62,18 @@ func NewCommitCommand() *cli.Command {
 				commit.Parents = append(commit.Parents, branch.Head)
 			}
 
+			if cc.Config.Merge != nil {
+				commit.Parents = append(commit.Parents, cc.Config.Merge.Head)
+			}
+
+			if cc.Config.Merge != nil && !c.IsSet("message") {
+				commit.Message = cc.Config.Merge.Message
+			}
+
+			if err := signCommit(commit, c.Bool("sign")); err != nil {
+				return err
+			}
+
 			commitID, err := object.AddCommit(c.Context, cc.DAG, commit)
 			if err != nil {
 				return err
@@ -65,7 +81,38 @@ func NewCommitCommand() *cli.Command {
 
 			branch.Head = commitID
 			branch.Stash = tree.Cid()
+			cc.Config.Merge = nil
 			return cc.Config.Write()
 		},
 	}
 }
+
+// signCommit sets the commit author and committer to the daemon identity.
+//
+// If sign is true the commit is signed with the daemon key.
+// Commits are created without an identity if the daemon is not initialized.
+func signCommit(commit *object.Commit, sign bool) error {
+	identity, err := context.Identity()
+	if os.IsNotExist(err) && !sign {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	committer := *identity
+	commit.Author = identity
+	commit.Committer = &committer
+
+	if !sign {
+		return nil
+	}
+
+	key, err := context.PrivateKey()
+	if err != nil {
+		return err
+	}
+
+	return commit.Sign(key)
+}
@@ -2,6 +2,7 @@ package context
 
 import (
 	"encoding/json"
+	"errors"
 	"os"
 	"path/filepath"
 
@@ -25,6 +26,16 @@ type Branch struct {
 	Remote string `json:"remote"`
 }
 
+// Merge contains info about a merge in progress.
+type Merge struct {
+	// Head is the CID of the commit being merged.
+	Head cid.Cid `json:"head"`
+	// Message is the description of the merge.
+	Message string `json:"message"`
+	// Conflicts contains the paths of conflicting files.
+	Conflicts []string `json:"conflicts"`
+}
+
 // Config contains repository info.
 type Config struct {
 	// Branch is the name of the current branch.
@@ -33,6 +44,10 @@ type Config struct {
 	Branches map[string]*Branch `json:"branches"`
 	// Remotes contains named remotes.
 	Remotes map[string]string `json:"remotes"`
+	// Tags contains named tag CIDs.
+	Tags map[string]cid.Cid `json:"tags"`
+	// Merge is set when a merge is in progress.
+	Merge *Merge `json:"merge,omitempty"`
 
 	path string
 }
@@ -45,10 +60,25 @@ func NewConfig(root string) *Config {
 			DefaultBranch: {},
 		},
 		Remotes: make(map[string]string),
+		Tags:    make(map[string]cid.Cid),
 		path:    filepath.Join(root, ConfigFile),
 	}
 }
 
+// Ref returns the head of the branch with the given name or the decoded commit CID.
+func (c *Config) Ref(name string) (cid.Cid, error) {
+	if branch, ok := c.Branches[name]; ok {
+		return branch.Head, nil
+	}
+
+	id, err := cid.Decode(name)
+	if err != nil {
+		return cid.Cid{}, errors.New("invalid branch or commit")
+	}
+
+	return id, nil
+}
+
 // Read reads the config from the path.
 func (c *Config) Read() error {
 	data, err := os.ReadFile(c.path)
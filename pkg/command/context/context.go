@@ -11,12 +11,26 @@ import (
 	offline "github.com/ipfs/go-ipfs-exchange-offline"
 	ipld "github.com/ipfs/go-ipld-format"
 	merkledag "github.com/ipfs/go-merkledag"
+	"github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/libp2p/go-libp2p-core/peer"
 
 	"github.com/multiverse-vcs/go-multiverse/internal/ignore"
+	"github.com/multiverse-vcs/go-multiverse/internal/p2p"
+	"github.com/multiverse-vcs/go-multiverse/pkg/fs"
+	"github.com/multiverse-vcs/go-multiverse/pkg/object"
+	"github.com/multiverse-vcs/go-multiverse/pkg/remote"
 )
 
-// DotDir is the name of the dot directory.
-const DotDir = ".multi"
+const (
+	// DotDir is the name of the dot directory.
+	DotDir = ".multi"
+	// IndexFile is the name of the working tree index file.
+	IndexFile = "index.json"
+	// ExcludeFile is the name of the unshared repository ignore file.
+	ExcludeFile = "info/exclude"
+	// GlobalIgnoreFile is the name of the user ignore file in the config dir.
+	GlobalIgnoreFile = "multiverse/ignore"
+)
 
 // DefaultIgnore contans the default ignore rules.
 var DefaultIgnore = ignore.New("", ".git", ".svn", ".hg", ".multi")
@@ -29,6 +43,10 @@ type Context struct {
 	Config *Config
 	// DAG contains all versioned files.
 	DAG ipld.DAGService
+	// Ignore contains the default, user, and repository ignore rules.
+	Ignore ignore.Filter
+	// Index caches the nodes of unchanged working tree files.
+	Index *fs.Index
 	// Root is the top level directory.
 	Root string
 }
@@ -60,6 +78,16 @@ func New(cwd string) (*Context, error) {
 		return nil, err
 	}
 
+	index := fs.NewIndex(filepath.Dir(root), filepath.Join(root, IndexFile))
+	if err := index.Read(); err != nil {
+		return nil, err
+	}
+
+	filter, err := loadIgnore(root)
+	if err != nil {
+		return nil, err
+	}
+
 	dpath := filepath.Join(root, "datastore")
 	dopts := badger.DefaultOptions
 
@@ -76,10 +104,108 @@ func New(cwd string) (*Context, error) {
 		Blocks: bstore,
 		Config: config,
 		DAG:    merkledag.NewDAGService(bserv),
+		Ignore: filter,
+		Index:  index,
 		Root:   filepath.Dir(root),
 	}, nil
 }
 
+// loadIgnore returns the ignore rules that apply to the entire repository.
+//
+// User rules are overridden by repository rules.
+func loadIgnore(root string) (ignore.Filter, error) {
+	dir := filepath.Dir(root)
+	filter := DefaultIgnore
+
+	config, err := os.UserConfigDir()
+	if err == nil {
+		global, err := ignore.LoadFile(dir, filepath.Join(config, GlobalIgnoreFile))
+		if err != nil {
+			return nil, err
+		}
+
+		filter = filter.Merge(global)
+	}
+
+	exclude, err := ignore.LoadFile(dir, filepath.Join(root, ExcludeFile))
+	if err != nil {
+		return nil, err
+	}
+
+	return filter.Merge(exclude), nil
+}
+
+// PrivateKey returns the private key of the local daemon.
+func PrivateKey() (crypto.PrivKey, error) {
+	config, err := remoteConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	return p2p.DecodeKey(config.PrivateKey)
+}
+
+// Identity returns the author identity of the local daemon.
+//
+// The name and email are read from the author metadata.
+func Identity() (*object.Identity, error) {
+	config, err := remoteConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := p2p.DecodeKey(config.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	peerID, err := peer.IDFromPrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &object.Identity{
+		PeerID: peerID,
+		Name:   config.Author.Metadata[object.NameKey],
+		Email:  config.Author.Metadata[object.EmailKey],
+	}, nil
+}
+
+// Daemon returns the peer info used to connect to the local daemon.
+func Daemon() (peer.AddrInfo, error) {
+	config, err := remoteConfig()
+	if err != nil {
+		return peer.AddrInfo{}, err
+	}
+
+	key, err := p2p.DecodeKey(config.PrivateKey)
+	if err != nil {
+		return peer.AddrInfo{}, err
+	}
+
+	peerID, err := peer.IDFromPrivateKey(key)
+	if err != nil {
+		return peer.AddrInfo{}, err
+	}
+
+	return p2p.LocalAddrInfo(peerID, config.ListenAddresses)
+}
+
+// remoteConfig returns the config of the local daemon.
+func remoteConfig() (*remote.Config, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+
+	config := remote.NewConfig(filepath.Join(home, remote.DotDir))
+	if err := config.Read(); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
 // Root searches for the repository root.
 func Root(root string) (string, error) {
 	path := filepath.Join(root, DotDir)
@@ -0,0 +1,295 @@
+package command
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	cid "github.com/ipfs/go-cid"
+	"github.com/ipfs/go-merkledag/dagutils"
+	unixfs "github.com/ipfs/go-unixfs"
+	"github.com/urfave/cli/v2"
+
+	"github.com/multiverse-vcs/go-multiverse/pkg/command/context"
+	"github.com/multiverse-vcs/go-multiverse/pkg/dag"
+	"github.com/multiverse-vcs/go-multiverse/pkg/diff"
+	"github.com/multiverse-vcs/go-multiverse/pkg/fs"
+	"github.com/multiverse-vcs/go-multiverse/pkg/object"
+)
+
+// NewDiffCommand returns a new cli command.
+func NewDiffCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "diff",
+		Usage: "Print changes between the working tree, branches, or commits",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "stat",
+				Usage: "Print a summary of changed lines",
+			},
+			&cli.BoolFlag{
+				Name:  "name-only",
+				Usage: "Print only the names of changed files",
+			},
+			&cli.StringSliceFlag{
+				Name:    "path",
+				Aliases: []string{"p"},
+				Usage:   "Limit changes to the given paths",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			if c.NArg() > 2 {
+				cli.ShowAppHelpAndExit(c, -1)
+			}
+
+			cwd, err := os.Getwd()
+			if err != nil {
+				return err
+			}
+
+			cc, err := context.New(cwd)
+			if err != nil {
+				return err
+			}
+
+			branch := cc.Config.Branches[cc.Config.Branch]
+			head := branch.Head
+
+			if c.NArg() > 0 {
+				head, err = cc.Config.Ref(c.Args().Get(0))
+				if err != nil {
+					return err
+				}
+			}
+
+			before, err := commitTree(c, cc, head)
+			if err != nil {
+				return err
+			}
+
+			var after cid.Cid
+			if c.NArg() > 1 {
+				id, err := cc.Config.Ref(c.Args().Get(1))
+				if err != nil {
+					return err
+				}
+
+				after, err = commitTree(c, cc, id)
+				if err != nil {
+					return err
+				}
+			} else {
+				tree, err := addTree(c, cc)
+				if err != nil {
+					return err
+				}
+
+				after = tree.Cid()
+			}
+
+			changes, err := dag.Changes(c.Context, cc.DAG, before, after)
+			if err != nil {
+				return err
+			}
+
+			renames, err := dag.Renames(c.Context, cc.DAG, before, changes)
+			if err != nil {
+				return err
+			}
+
+			renames = filterChanges(renames, c.StringSlice("path"))
+
+			switch {
+			case c.Bool("name-only"):
+				for _, change := range renames {
+					fmt.Println(change.Path)
+				}
+
+				return nil
+			case c.Bool("stat"):
+				return printDiffStat(c, cc, renames)
+			}
+
+			for _, change := range renames {
+				if err := printDiff(c, cc, change); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		},
+	}
+}
+
+// commitTree returns the tree CID of the commit with id.
+func commitTree(c *cli.Context, cc *context.Context, id cid.Cid) (cid.Cid, error) {
+	if !id.Defined() {
+		return cid.Cid{}, nil
+	}
+
+	commit, err := object.GetCommit(c.Context, cc.DAG, id)
+	if err != nil {
+		return cid.Cid{}, err
+	}
+
+	return commit.Tree, nil
+}
+
+// filterChanges returns the changes within any of the given paths.
+func filterChanges(changes []*dag.Change, paths []string) []*dag.Change {
+	if len(paths) == 0 {
+		return changes
+	}
+
+	var out []*dag.Change
+	for _, change := range changes {
+		for _, p := range paths {
+			if matchPath(change.Path, p) || matchPath(change.From, p) {
+				out = append(out, change)
+				break
+			}
+		}
+	}
+
+	return out
+}
+
+// matchPath returns true if name is equal to or contained in the path p.
+func matchPath(name, p string) bool {
+	p = strings.Trim(p, "/")
+	return name != "" && (name == p || strings.HasPrefix(name, p+"/"))
+}
+
+// readText returns the contents of the file with id or an empty string if undefined.
+func readText(c *cli.Context, cc *context.Context, id cid.Cid) (string, error) {
+	if !id.Defined() {
+		return "", nil
+	}
+
+	node, err := cc.DAG.Get(c.Context, id)
+	if err != nil {
+		return "", err
+	}
+
+	fsnode, err := unixfs.ExtractFSNode(node)
+	if err != nil {
+		return "", err
+	}
+
+	// symlinks are compared by target
+	if fsnode.Type() == unixfs.TSymlink {
+		return string(fsnode.Data()), nil
+	}
+
+	return fs.Cat(c.Context, cc.DAG, id)
+}
+
+// printDiff prints the unified diff of the given change.
+func printDiff(c *cli.Context, cc *context.Context, change *dag.Change) error {
+	textA, err := readText(c, cc, change.Before)
+	if err != nil {
+		return err
+	}
+
+	textB, err := readText(c, cc, change.After)
+	if err != nil {
+		return err
+	}
+
+	nameA := "a/" + change.Path
+	nameB := "b/" + change.Path
+
+	if change.From != "" {
+		nameA = "a/" + change.From
+	}
+
+	fmt.Printf("diff %s %s\n", nameA, nameB)
+
+	modeA, modeB, err := dag.Modes(c.Context, cc.DAG, change)
+	if err != nil {
+		return err
+	}
+
+	switch change.Type {
+	case dagutils.Add:
+		fmt.Printf("new file mode %04o\n", modeB)
+		nameA = "/dev/null"
+	case dagutils.Remove:
+		fmt.Printf("deleted file mode %04o\n", modeA)
+		nameB = "/dev/null"
+	case dag.Rename:
+		fmt.Printf("similarity index %d%%\n", change.Similarity)
+		fmt.Printf("rename from %s\n", change.From)
+		fmt.Printf("rename to %s\n", change.Path)
+	case dag.Copy:
+		fmt.Printf("similarity index %d%%\n", change.Similarity)
+		fmt.Printf("copy from %s\n", change.From)
+		fmt.Printf("copy to %s\n", change.Path)
+	}
+
+	if change.Type != dagutils.Add && change.Type != dagutils.Remove && modeA != modeB {
+		fmt.Printf("old mode %04o\n", modeA)
+		fmt.Printf("new mode %04o\n", modeB)
+	}
+
+	if textA == textB {
+		return nil
+	}
+
+	if diff.IsBinary(textA) || diff.IsBinary(textB) {
+		fmt.Printf("Binary files %s and %s differ\n", nameA, nameB)
+		return nil
+	}
+
+	return diff.Unified(os.Stdout, nameA, nameB, textA, textB)
+}
+
+// printDiffStat prints a summary of inserted and deleted lines.
+func printDiffStat(c *cli.Context, cc *context.Context, changes []*dag.Change) error {
+	names := make([]string, len(changes))
+	for i, change := range changes {
+		names[i] = change.Path
+		if change.From != "" {
+			names[i] = fmt.Sprintf("%s => %s", change.From, change.Path)
+		}
+	}
+
+	var width int
+	for _, name := range names {
+		if len(name) > width {
+			width = len(name)
+		}
+	}
+
+	var inserts, deletes int
+	for i, change := range changes {
+		textA, err := readText(c, cc, change.Before)
+		if err != nil {
+			return err
+		}
+
+		textB, err := readText(c, cc, change.After)
+		if err != nil {
+			return err
+		}
+
+		if diff.IsBinary(textA) || diff.IsBinary(textB) {
+			fmt.Printf(" %-*s | Bin\n", width, names[i])
+			continue
+		}
+
+		insert, delete := diff.Stat(diff.Lines(textA, textB))
+		inserts, deletes = inserts+insert, deletes+delete
+
+		graph := strings.Repeat("+", insert) + strings.Repeat("-", delete)
+		if len(graph) > 50 {
+			scale := float64(50) / float64(len(graph))
+			graph = strings.Repeat("+", int(float64(insert)*scale)) + strings.Repeat("-", int(float64(delete)*scale))
+		}
+
+		fmt.Printf(" %-*s | %d %s\n", width, names[i], insert+delete, graph)
+	}
+
+	fmt.Printf(" %d files changed, %d insertions(+), %d deletions(-)\n", len(changes), inserts, deletes)
+	return nil
+}
@@ -0,0 +1,125 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/multiverse-vcs/go-multiverse/pkg/command/context"
+	"github.com/multiverse-vcs/go-multiverse/pkg/dag"
+	"github.com/multiverse-vcs/go-multiverse/pkg/rpc"
+	"github.com/multiverse-vcs/go-multiverse/pkg/rpc/repo"
+)
+
+// NewFsckCommand returns a new cli command.
+func NewFsckCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "fsck",
+		Usage: "Verify the integrity of stored objects",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "daemon",
+				Usage: "Check the daemon store instead of the local repo",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			var count int
+			var problems []*dag.Problem
+
+			if c.Bool("daemon") {
+				client, err := rpc.NewClient()
+				if err != nil {
+					return cli.Exit(rpc.DialErrMsg, -1)
+				}
+
+				var reply repo.FsckReply
+				if err := client.Call("Repo.Fsck", &repo.FsckArgs{}, &reply); err != nil {
+					return err
+				}
+
+				count, problems = reply.Count, reply.Problems
+			} else {
+				cwd, err := os.Getwd()
+				if err != nil {
+					return err
+				}
+
+				cc, err := context.New(cwd)
+				if err != nil {
+					return err
+				}
+
+				checker, err := fsckLocal(c, cc)
+				if err != nil {
+					return err
+				}
+
+				count, problems = checker.Count, checker.Problems
+			}
+
+			for _, p := range problems {
+				fmt.Println(p.String())
+			}
+
+			fmt.Printf("checked %d objects\n", count)
+			if len(problems) != 0 {
+				return errors.New("repository is damaged")
+			}
+
+			return nil
+		},
+	}
+}
+
+// fsckLocal checks all objects referenced by branches, stashes, tags, and merges.
+func fsckLocal(c *cli.Context, cc *context.Context) (*dag.Checker, error) {
+	checker := dag.NewChecker(cc.Blocks)
+
+	var names []string
+	for name := range cc.Config.Branches {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		branch := cc.Config.Branches[name]
+
+		// new branches can have a stash before the first commit
+		if branch.Head.Defined() {
+			if err := checker.Check(c.Context, "branch "+name, dag.CommitObject, branch.Head); err != nil {
+				return nil, err
+			}
+		}
+
+		if branch.Stash.Defined() {
+			if err := checker.Check(c.Context, "stash "+name, dag.TreeObject, branch.Stash); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	names = nil
+	for name := range cc.Config.Tags {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		if err := checker.Check(c.Context, "tag "+name, dag.TagObject, cc.Config.Tags[name]); err != nil {
+			return nil, err
+		}
+	}
+
+	if cc.Config.Merge != nil {
+		if err := checker.Check(c.Context, "merge", dag.CommitObject, cc.Config.Merge.Head); err != nil {
+			return nil, err
+		}
+	}
+
+	return checker, nil
+}
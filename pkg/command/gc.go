@@ -0,0 +1,77 @@
+package command
+
+import (
+	"fmt"
+	"os"
+
+	cid "github.com/ipfs/go-cid"
+	"github.com/urfave/cli/v2"
+
+	"github.com/multiverse-vcs/go-multiverse/pkg/command/context"
+	"github.com/multiverse-vcs/go-multiverse/pkg/dag"
+)
+
+// NewGCCommand returns a new cli command.
+func NewGCCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "gc",
+		Usage: "Remove unreachable objects from the local store",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:    "dry-run",
+				Aliases: []string{"n"},
+				Usage:   "Print how much would be removed",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			cwd, err := os.Getwd()
+			if err != nil {
+				return err
+			}
+
+			cc, err := context.New(cwd)
+			if err != nil {
+				return err
+			}
+
+			dryRun := c.Bool("dry-run")
+
+			count, size, err := dag.GC(c.Context, cc.Blocks, cc.DAG, gcRoots(cc), dryRun)
+			if err != nil {
+				return err
+			}
+
+			if dryRun {
+				fmt.Printf("would remove %d objects (%d bytes)\n", count, size)
+			} else {
+				fmt.Printf("removed %d objects (%d bytes)\n", count, size)
+			}
+
+			return nil
+		},
+	}
+}
+
+// gcRoots returns the CIDs of all objects that must be kept.
+//
+// Index entries are kept so unchanged files do not need to be added again.
+func gcRoots(cc *context.Context) []cid.Cid {
+	var roots []cid.Cid
+	for _, branch := range cc.Config.Branches {
+		roots = append(roots, branch.Head, branch.Stash)
+	}
+
+	for _, id := range cc.Config.Tags {
+		roots = append(roots, id)
+	}
+
+	if cc.Config.Merge != nil {
+		roots = append(roots, cc.Config.Merge.Head)
+	}
+
+	for _, entry := range cc.Index.Entries {
+		roots = append(roots, entry.ID)
+	}
+
+	return roots
+}
@@ -3,6 +3,8 @@ package command
 import (
 	"fmt"
 	"os"
+	"sort"
+	"strings"
 
 	cid "github.com/ipfs/go-cid"
 	"github.com/multiverse-vcs/go-multiverse/pkg/command/context"
@@ -16,6 +18,12 @@ func NewLogCommand() *cli.Command {
 	return &cli.Command{
 		Name:  "log",
 		Usage: "Print branch history",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "verify",
+				Usage: "Check commit signatures",
+			},
+		},
 		Action: func(c *cli.Context) error {
 			cwd, err := os.Getwd()
 			if err != nil {
@@ -29,13 +37,31 @@ func NewLogCommand() *cli.Command {
 
 			branch := cc.Config.Branches[cc.Config.Branch]
 
+			tags, err := commitTags(c, cc)
+			if err != nil {
+				return err
+			}
+
 			visit := func(id cid.Cid) bool {
 				commit, err := object.GetCommit(c.Context, cc.DAG, id)
 				if err != nil {
 					return false
 				}
 
-				fmt.Printf("commit %s\n", id.String())
+				fmt.Printf("commit %s", id.String())
+				if names, ok := tags[id]; ok {
+					fmt.Printf(" (tag: %s)", strings.Join(names, ", tag: "))
+				}
+				fmt.Println()
+
+				if c.Bool("verify") {
+					printSignature(commit)
+				}
+
+				if commit.Author != nil {
+					fmt.Printf("Author: %s\n", commit.Author.String())
+				}
+
 				fmt.Printf("Date:  %s\n", commit.Date.Format("Mon Jan 02 15:04:05 2006 -0700"))
 				fmt.Printf("\n\t%s\n\n", commit.Message)
 				return true
@@ -45,3 +71,41 @@ func NewLogCommand() *cli.Command {
 		},
 	}
 }
+
+// commitTags returns a map of commit CIDs to local tag names.
+func commitTags(c *cli.Context, cc *context.Context) (map[cid.Cid][]string, error) {
+	var names []string
+	for name := range cc.Config.Tags {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	tags := make(map[cid.Cid][]string)
+	for _, name := range names {
+		tag, err := object.GetTag(c.Context, cc.DAG, cc.Config.Tags[name])
+		if err != nil {
+			return nil, err
+		}
+
+		tags[tag.Target] = append(tags[tag.Target], name)
+	}
+
+	return tags, nil
+}
+
+// printSignature prints the signature status of the commit.
+func printSignature(commit *object.Commit) {
+	if len(commit.Signature) == 0 {
+		fmt.Printf("No signature\n")
+		return
+	}
+
+	match, err := commit.Verify()
+	if err != nil || !match {
+		fmt.Printf("BAD signature\n")
+		return
+	}
+
+	fmt.Printf("Good signature from %s\n", commit.Author.PeerID.Pretty())
+}
@@ -0,0 +1,206 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	cid "github.com/ipfs/go-cid"
+	"github.com/urfave/cli/v2"
+
+	"github.com/multiverse-vcs/go-multiverse/pkg/command/context"
+	"github.com/multiverse-vcs/go-multiverse/pkg/dag"
+	"github.com/multiverse-vcs/go-multiverse/pkg/fs"
+	"github.com/multiverse-vcs/go-multiverse/pkg/merge"
+	"github.com/multiverse-vcs/go-multiverse/pkg/object"
+)
+
+// mergeOptions contains merge settings.
+type mergeOptions struct {
+	// Message is the description of the merge commit.
+	Message string
+	// FastForwardOnly fails if a merge commit is required.
+	FastForwardOnly bool
+	// NoFastForward creates a merge commit even when fast forwarding.
+	NoFastForward bool
+	// Sign signs the merge commit with the daemon key.
+	Sign bool
+}
+
+// mergeFlags are shared by commands that merge commits.
+var mergeFlags = []cli.Flag{
+	&cli.StringFlag{
+		Name:    "message",
+		Aliases: []string{"m"},
+		Usage:   "Description of the merge",
+	},
+	&cli.BoolFlag{
+		Name:  "ff-only",
+		Usage: "Refuse to merge unless fast forward is possible",
+	},
+	&cli.BoolFlag{
+		Name:  "no-ff",
+		Usage: "Create a merge commit even when fast forward is possible",
+	},
+	&cli.BoolFlag{
+		Name:    "sign",
+		Aliases: []string{"s"},
+		Usage:   "Sign the merge commit with the daemon key",
+	},
+}
+
+// NewMergeCommand returns a new cli command.
+func NewMergeCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "merge",
+		Usage: "Combine changes from a branch or commit",
+		Flags: mergeFlags,
+		Action: func(c *cli.Context) error {
+			if c.NArg() != 1 {
+				cli.ShowAppHelpAndExit(c, -1)
+			}
+
+			cwd, err := os.Getwd()
+			if err != nil {
+				return err
+			}
+
+			cc, err := context.New(cwd)
+			if err != nil {
+				return err
+			}
+
+			if cc.Config.Merge != nil {
+				return errors.New("merge in progress")
+			}
+
+			name := c.Args().Get(0)
+			if name == cc.Config.Branch {
+				return errors.New("cannot merge branch into itself")
+			}
+
+			id, err := cc.Config.Ref(name)
+			if err != nil {
+				return err
+			}
+
+			if !id.Defined() {
+				return errors.New("nothing to merge")
+			}
+
+			branch := cc.Config.Branches[cc.Config.Branch]
+
+			stash, err := addTree(c, cc)
+			if err != nil {
+				return err
+			}
+
+			status, err := dag.Status(c.Context, cc.DAG, stash, branch.Head)
+			if err != nil {
+				return err
+			}
+
+			if len(status) != 0 {
+				return errors.New("uncommitted changes")
+			}
+
+			opts := mergeOptions{
+				Message:         fmt.Sprintf("merge %s", name),
+				FastForwardOnly: c.Bool("ff-only"),
+				NoFastForward:   c.Bool("no-ff"),
+				Sign:            c.Bool("sign"),
+			}
+
+			if c.IsSet("message") {
+				opts.Message = c.String("message")
+			}
+
+			return mergeHead(c, cc, id, opts)
+		},
+	}
+}
+
+// mergeHead merges the commit with the given id into the current branch.
+func mergeHead(c *cli.Context, cc *context.Context, id cid.Cid, opts mergeOptions) error {
+	if opts.FastForwardOnly && opts.NoFastForward {
+		return errors.New("ff-only and no-ff cannot be combined")
+	}
+
+	branch := cc.Config.Branches[cc.Config.Branch]
+
+	base, err := merge.Base(c.Context, cc.DAG, branch.Head, id)
+	if err != nil {
+		return err
+	}
+
+	// commit is already contained in local history
+	if base == id {
+		return nil
+	}
+
+	if base != branch.Head && opts.FastForwardOnly {
+		return errors.New("not possible to fast forward")
+	}
+
+	result, err := merge.Tree(c.Context, cc.DAG, base, branch.Head, id)
+	if err != nil {
+		return err
+	}
+
+	if err := fs.Checkout(c.Context, cc.DAG, cc.Root, result.Tree, cc.Ignore); err != nil {
+		return err
+	}
+
+	if len(result.Conflicts) != 0 {
+		cc.Config.Merge = &context.Merge{
+			Head:      id,
+			Message:   opts.Message,
+			Conflicts: result.Paths(),
+		}
+
+		branch.Stash = result.Tree.Cid()
+		if err := cc.Config.Write(); err != nil {
+			return err
+		}
+
+		printConflicts(result.Conflicts)
+		return errors.New("fix conflicts and then commit the result")
+	}
+
+	head := id
+	if base != branch.Head || (opts.NoFastForward && branch.Head.Defined()) {
+		commit := object.NewCommit()
+		commit.Tree = result.Tree.Cid()
+		commit.Message = opts.Message
+		commit.Parents = []cid.Cid{branch.Head, id}
+
+		if err := signCommit(commit, opts.Sign); err != nil {
+			return err
+		}
+
+		head, err = object.AddCommit(c.Context, cc.DAG, commit)
+		if err != nil {
+			return err
+		}
+	}
+
+	branch.Head = head
+	branch.Stash = result.Tree.Cid()
+	return cc.Config.Write()
+}
+
+// printConflicts prints a summary of the given merge conflicts.
+func printConflicts(conflicts []*merge.Conflict) {
+	for _, c := range conflicts {
+		switch {
+		case c.Binary:
+			fmt.Printf("conflict (binary): %s\n", c.Path)
+		case c.Removed:
+			fmt.Printf("conflict (removed): %s\n", c.Path)
+		case c.Renamed:
+			fmt.Printf("conflict (renamed): %s\n", c.Path)
+		default:
+			fmt.Printf("conflict (content): %s\n", c.Path)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package command
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	cid "github.com/ipfs/go-cid"
+	ufsio "github.com/ipfs/go-unixfs/io"
+	"github.com/urfave/cli/v2"
+
+	"github.com/multiverse-vcs/go-multiverse/pkg/command/context"
+	"github.com/multiverse-vcs/go-multiverse/pkg/dag"
+	"github.com/multiverse-vcs/go-multiverse/pkg/object"
+)
+
+// newTestContext returns a command context for a new repo in a temp dir.
+func newTestContext(t *testing.T) (*cli.Context, *context.Context) {
+	dir, err := ioutil.TempDir("", "multi-*")
+	if err != nil {
+		t.Fatal("failed to create temp dir")
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	if err := context.Init(dir); err != nil {
+		t.Fatal("failed to init repo")
+	}
+
+	cc, err := context.New(dir)
+	if err != nil {
+		t.Fatal("failed to create context")
+	}
+
+	return cli.NewContext(cli.NewApp(), nil, nil), cc
+}
+
+// addCommit adds a commit with a tree containing the given files.
+func addCommit(t *testing.T, c *cli.Context, cc *context.Context, files map[string]string, parents ...cid.Cid) cid.Cid {
+	dir := ufsio.NewDirectory(cc.DAG)
+	for name, content := range files {
+		file, err := dag.Chunk(c.Context, cc.DAG, strings.NewReader(content))
+		if err != nil {
+			t.Fatal("failed to add file")
+		}
+
+		if err := dir.AddChild(c.Context, name, file); err != nil {
+			t.Fatal("failed to add child")
+		}
+	}
+
+	tree, err := dir.GetNode()
+	if err != nil {
+		t.Fatal("failed to get tree")
+	}
+
+	if err := cc.DAG.Add(c.Context, tree); err != nil {
+		t.Fatal("failed to add tree")
+	}
+
+	commit := object.NewCommit()
+	commit.Tree = tree.Cid()
+	commit.Parents = parents
+	commit.Date = time.Now()
+
+	id, err := object.AddCommit(c.Context, cc.DAG, commit)
+	if err != nil {
+		t.Fatal("failed to add commit")
+	}
+
+	return id
+}
+
+func TestMergeHead(t *testing.T) {
+	c, cc := newTestContext(t)
+
+	base := addCommit(t, c, cc, map[string]string{"README": "base"})
+	local := addCommit(t, c, cc, map[string]string{"README": "base", "local": "local"}, base)
+	remote := addCommit(t, c, cc, map[string]string{"README": "base", "remote": "remote"}, base)
+
+	branch := cc.Config.Branches[cc.Config.Branch]
+	branch.Head = local
+
+	if err := mergeHead(c, cc, remote, mergeOptions{Message: "merge"}); err != nil {
+		t.Fatalf("failed to merge %v", err)
+	}
+
+	commit, err := object.GetCommit(c.Context, cc.DAG, branch.Head)
+	if err != nil {
+		t.Fatal("failed to get commit")
+	}
+
+	if len(commit.Parents) != 2 || commit.Parents[0] != local || commit.Parents[1] != remote {
+		t.Errorf("expected merge commit parents to be local and remote heads")
+	}
+
+	if commit.Message != "merge" {
+		t.Errorf("unexpected merge message %q", commit.Message)
+	}
+
+	if branch.Stash != commit.Tree {
+		t.Errorf("expected stash to be merged tree")
+	}
+}
+
+func TestMergeHeadFastForward(t *testing.T) {
+	c, cc := newTestContext(t)
+
+	base := addCommit(t, c, cc, map[string]string{"README": "base"})
+	remote := addCommit(t, c, cc, map[string]string{"README": "remote"}, base)
+
+	branch := cc.Config.Branches[cc.Config.Branch]
+	branch.Head = base
+
+	if err := mergeHead(c, cc, remote, mergeOptions{Message: "merge"}); err != nil {
+		t.Fatalf("failed to merge %v", err)
+	}
+
+	if branch.Head != remote {
+		t.Errorf("expected head to fast forward to remote head")
+	}
+
+	data, err := ioutil.ReadFile(filepath.Join(cc.Root, "README"))
+	if err != nil {
+		t.Fatal("failed to read file")
+	}
+
+	if string(data) != "remote" {
+		t.Errorf("expected working tree to be checked out")
+	}
+}
@@ -1,17 +1,16 @@
 package command
 
 import (
-	"bytes"
 	"errors"
+	"fmt"
 	"os"
 
 	cid "github.com/ipfs/go-cid"
 	"github.com/urfave/cli/v2"
 
+	"github.com/multiverse-vcs/go-multiverse/internal/p2p"
 	"github.com/multiverse-vcs/go-multiverse/pkg/command/context"
 	"github.com/multiverse-vcs/go-multiverse/pkg/dag"
-	"github.com/multiverse-vcs/go-multiverse/pkg/fs"
-	"github.com/multiverse-vcs/go-multiverse/pkg/merge"
 	"github.com/multiverse-vcs/go-multiverse/pkg/rpc"
 	"github.com/multiverse-vcs/go-multiverse/pkg/rpc/repo"
 )
@@ -21,7 +20,7 @@ func NewPullCommand() *cli.Command {
 	return &cli.Command{
 		Name:  "pull",
 		Usage: "Update a local branch with remote changes",
-		Flags: []cli.Flag{
+		Flags: append([]cli.Flag{
 			&cli.StringFlag{
 				Name:    "remote",
 				Aliases: []string{"r"},
@@ -32,7 +31,7 @@ func NewPullCommand() *cli.Command {
 				Aliases: []string{"b"},
 				Usage:   "Remote branch name",
 			},
-		},
+		}, mergeFlags...),
 		Action: func(c *cli.Context) error {
 			cwd, err := os.Getwd()
 			if err != nil {
@@ -49,11 +48,15 @@ func NewPullCommand() *cli.Command {
 				return cli.Exit(rpc.DialErrMsg, -1)
 			}
 
+			if cc.Config.Merge != nil {
+				return errors.New("merge in progress")
+			}
+
 			branch := cc.Config.Branches[cc.Config.Branch]
 			remote := branch.Remote
 			source := cc.Config.Branch
 
-			stash, err := fs.Add(c.Context, cc.DAG, cc.Root, context.DefaultIgnore)
+			stash, err := addTree(c, cc)
 			if err != nil {
 				return err
 			}
@@ -91,7 +94,6 @@ func NewPullCommand() *cli.Command {
 			args := repo.PullArgs{
 				Remote: remote,
 				Branch: source,
-				Refs:   refs,
 			}
 
 			var reply repo.PullReply
@@ -99,28 +101,29 @@ func NewPullCommand() *cli.Command {
 				return err
 			}
 
-			root, err := dag.ReadCar(cc.Blocks, bytes.NewReader(reply.Data))
+			h, daemonID, err := dialDaemon(c, cc)
 			if err != nil {
 				return err
 			}
+			defer h.Close()
 
-			base, err := merge.Base(c.Context, cc.DAG, branch.Head, root)
-			if err != nil {
+			want := []cid.Cid{reply.Head}
+			if err := p2p.Fetch(c.Context, h, daemonID, cc.Blocks, want, refs); err != nil {
 				return err
 			}
 
-			tree, err := merge.Tree(c.Context, cc.DAG, base, branch.Head, root)
-			if err != nil {
-				return err
+			opts := mergeOptions{
+				Message:         fmt.Sprintf("merge %s/%s", remote, source),
+				FastForwardOnly: c.Bool("ff-only"),
+				NoFastForward:   c.Bool("no-ff"),
+				Sign:            c.Bool("sign"),
 			}
 
-			if err := fs.Write(c.Context, cc.DAG, cc.Root, tree); err != nil {
-				return err
+			if c.IsSet("message") {
+				opts.Message = c.String("message")
 			}
 
-			branch.Head = root
-			branch.Stash = tree.Cid()
-			return cc.Config.Write()
+			return mergeHead(c, cc, reply.Head, opts)
 		},
 	}
 }
@@ -1,15 +1,18 @@
 package command
 
 import (
-	"bytes"
 	"errors"
+	"fmt"
+	netrpc "net/rpc"
 	"os"
+	"sort"
 
+	"github.com/libp2p/go-libp2p-core/peer"
 	"github.com/urfave/cli/v2"
 
 	"github.com/multiverse-vcs/go-multiverse/pkg/command/context"
-	"github.com/multiverse-vcs/go-multiverse/pkg/dag"
 	"github.com/multiverse-vcs/go-multiverse/pkg/merge"
+	"github.com/multiverse-vcs/go-multiverse/pkg/object"
 	"github.com/multiverse-vcs/go-multiverse/pkg/rpc"
 	"github.com/multiverse-vcs/go-multiverse/pkg/rpc/repo"
 )
@@ -30,6 +33,15 @@ func NewPushCommand() *cli.Command {
 				Aliases: []string{"b"},
 				Usage:   "Remote branch name",
 			},
+			&cli.BoolFlag{
+				Name:  "tags",
+				Usage: "Push all local tags",
+			},
+			&cli.BoolFlag{
+				Name:    "force",
+				Aliases: []string{"f"},
+				Usage:   "Replace existing remote tags",
+			},
 		},
 		Action: func(c *cli.Context) error {
 			cwd, err := os.Getwd()
@@ -51,10 +63,6 @@ func NewPushCommand() *cli.Command {
 			remote := branch.Remote
 			target := cc.Config.Branch
 
-			if !branch.Head.Defined() {
-				return errors.New("nothing to push")
-			}
-
 			if c.IsSet("remote") {
 				remote = c.String("remote")
 			}
@@ -76,7 +84,20 @@ func NewPushCommand() *cli.Command {
 				return err
 			}
 
-			refs := reply.Repository.Heads()
+			if !c.Bool("tags") && !branch.Head.Defined() {
+				return errors.New("nothing to push")
+			}
+
+			h, _, err := dialDaemon(c, cc)
+			if err != nil {
+				return err
+			}
+			defer h.Close()
+
+			if c.Bool("tags") {
+				return pushTags(c, cc, client, h.ID(), remote, reply.Repository)
+			}
+
 			head := reply.Repository.Branches[target]
 
 			base, err := merge.Base(c.Context, cc.DAG, head, branch.Head)
@@ -88,18 +109,48 @@ func NewPushCommand() *cli.Command {
 				return errors.New("branches are non-divergent")
 			}
 
-			var data bytes.Buffer
-			if err := dag.WriteCar(c.Context, cc.DAG, branch.Head, refs, &data); err != nil {
-				return err
-			}
-
 			pushArgs := repo.PushArgs{
 				Remote: remote,
 				Branch: target,
-				Data:   data.Bytes(),
+				Head:   branch.Head,
+				PeerID: h.ID(),
 			}
 
 			return client.Call("Repo.Push", &pushArgs, nil)
 		},
 	}
 }
+
+// pushTags updates the remote repository tags with all local tags.
+//
+// The daemon fetches the tag objects from the peer with the given id.
+func pushTags(c *cli.Context, cc *context.Context, client *netrpc.Client, peerID peer.ID, remote string, repository *object.Repository) error {
+	var names []string
+	for name := range cc.Config.Tags {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	for _, name := range names {
+		id := cc.Config.Tags[name]
+		if repository.Tags[name] == id {
+			continue
+		}
+
+		args := repo.TagArgs{
+			Remote: remote,
+			Tag:    name,
+			Force:  c.Bool("force"),
+			ID:     id,
+			PeerID: peerID,
+		}
+
+		if err := client.Call("Repo.Tag", &args, nil); err != nil {
+			return err
+		}
+
+		fmt.Printf("pushed tag %s\n", name)
+	}
+
+	return nil
+}
@@ -0,0 +1,42 @@
+package repo
+
+import (
+	"path/filepath"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/multiverse-vcs/go-multiverse/pkg/rpc"
+	"github.com/multiverse-vcs/go-multiverse/pkg/rpc/repo"
+)
+
+// NewExportCommand returns a new command.
+func NewExportCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "export",
+		Usage:     "Export a repository to a git repository",
+		ArgsUsage: "<remote> <path>",
+		Action: func(c *cli.Context) error {
+			if c.NArg() != 2 {
+				cli.ShowSubcommandHelpAndExit(c, 1)
+			}
+
+			path, err := filepath.Abs(c.Args().Get(1))
+			if err != nil {
+				return err
+			}
+
+			client, err := rpc.NewClient()
+			if err != nil {
+				return cli.Exit(rpc.DialErrMsg, -1)
+			}
+
+			args := repo.ExportArgs{
+				Remote: c.Args().Get(0),
+				Path:   path,
+			}
+
+			var reply repo.ExportReply
+			return client.Call("Repo.Export", &args, &reply)
+		},
+	}
+}
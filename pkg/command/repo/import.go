@@ -2,6 +2,7 @@ package repo
 
 import (
 	"fmt"
+	"path/filepath"
 
 	"github.com/urfave/cli/v2"
 
@@ -23,25 +24,45 @@ func NewImportCommand() *cli.Command {
 				Name:  "path",
 				Usage: "Repository path",
 			},
+			&cli.BoolFlag{
+				Name:  "update",
+				Usage: "Import new commits into an existing repository",
+			},
+			&cli.BoolFlag{
+				Name:  "force",
+				Usage: "Overwrite branches that are not fast-forwards",
+			},
 		},
 		Action: func(c *cli.Context) error {
 			if c.NArg() == 0 {
 				cli.ShowSubcommandHelpAndExit(c, 1)
 			}
 
-			if !c.IsSet("url") && !c.IsSet("path") {
+			if !c.IsSet("url") && !c.IsSet("path") && !c.Bool("update") {
 				cli.ShowSubcommandHelpAndExit(c, 1)
 			}
 
+			var path string
+			if c.IsSet("path") {
+				abs, err := filepath.Abs(c.String("path"))
+				if err != nil {
+					return err
+				}
+
+				path = abs
+			}
+
 			client, err := rpc.NewClient()
 			if err != nil {
 				return cli.Exit(rpc.DialErrMsg, -1)
 			}
 
 			args := repo.ImportArgs{
-				Name: c.Args().Get(0),
-				URL:  c.String("url"),
-				Path: c.String("path"),
+				Name:   c.Args().Get(0),
+				URL:    c.String("url"),
+				Path:   path,
+				Update: c.Bool("update"),
+				Force:  c.Bool("force"),
 			}
 
 			var reply repo.ImportReply
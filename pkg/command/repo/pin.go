@@ -0,0 +1,56 @@
+package repo
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/multiverse-vcs/go-multiverse/pkg/rpc"
+	"github.com/multiverse-vcs/go-multiverse/pkg/rpc/repo"
+	"github.com/urfave/cli/v2"
+)
+
+// NewPinCommand returns a new command.
+func NewPinCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "pin",
+		Usage:     "Keep a copy of a repository on the daemon",
+		ArgsUsage: "[remote]",
+		Action: func(c *cli.Context) error {
+			if c.NArg() > 1 {
+				cli.ShowSubcommandHelpAndExit(c, 1)
+			}
+
+			client, err := rpc.NewClient()
+			if err != nil {
+				return cli.Exit(rpc.DialErrMsg, -1)
+			}
+
+			args := repo.PinArgs{
+				Remote: c.Args().Get(0),
+			}
+
+			var reply repo.PinReply
+			if err := client.Call("Repo.Pin", &args, &reply); err != nil {
+				return err
+			}
+
+			if c.NArg() == 1 {
+				fmt.Printf("pinned %s\n", reply.Remote)
+				return nil
+			}
+
+			var pins []string
+			for pin := range reply.Pins {
+				pins = append(pins, pin)
+			}
+
+			sort.Strings(pins)
+
+			for _, pin := range pins {
+				fmt.Printf("%s %s\n", pin, reply.Pins[pin].String())
+			}
+
+			return nil
+		},
+	}
+}
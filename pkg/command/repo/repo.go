@@ -15,6 +15,9 @@ func NewCommand() *cli.Command {
 			NewListCommand(),
 			NewDeleteCommand(),
 			NewImportCommand(),
+			NewExportCommand(),
+			NewPinCommand(),
+			NewUnpinCommand(),
 		},
 	}
 }
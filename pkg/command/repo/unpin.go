@@ -0,0 +1,40 @@
+package repo
+
+import (
+	"fmt"
+
+	"github.com/multiverse-vcs/go-multiverse/pkg/rpc"
+	"github.com/multiverse-vcs/go-multiverse/pkg/rpc/repo"
+	"github.com/urfave/cli/v2"
+)
+
+// NewUnpinCommand returns a new command.
+func NewUnpinCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "unpin",
+		Usage:     "Allow a pinned repository to be removed from the daemon",
+		ArgsUsage: "<remote>",
+		Action: func(c *cli.Context) error {
+			if c.NArg() != 1 {
+				cli.ShowSubcommandHelpAndExit(c, 1)
+			}
+
+			client, err := rpc.NewClient()
+			if err != nil {
+				return cli.Exit(rpc.DialErrMsg, -1)
+			}
+
+			args := repo.UnpinArgs{
+				Remote: c.Args().Get(0),
+			}
+
+			var reply repo.UnpinReply
+			if err := client.Call("Repo.Unpin", &args, &reply); err != nil {
+				return err
+			}
+
+			fmt.Printf("unpinned %s\n", reply.Remote)
+			return nil
+		},
+	}
+}
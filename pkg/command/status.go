@@ -3,8 +3,8 @@ package command
 import (
 	"fmt"
 	"os"
-	"sort"
 
+	ipld "github.com/ipfs/go-ipld-format"
 	"github.com/ipfs/go-merkledag/dagutils"
 	"github.com/urfave/cli/v2"
 
@@ -29,7 +29,7 @@ func NewStatusCommand() *cli.Command {
 				return err
 			}
 
-			tree, err := fs.Add(c.Context, cc.DAG, cc.Root, context.DefaultIgnore)
+			tree, err := addTree(c, cc)
 			if err != nil {
 				return err
 			}
@@ -40,24 +40,35 @@ func NewStatusCommand() *cli.Command {
 				return err
 			}
 
-			paths := make([]string, 0)
-			for path := range status {
-				paths = append(paths, path)
-			}
-			sort.Strings(paths)
-
 			fmt.Printf("Tracking changes on branch %s:\n", cc.Config.Branch)
 			fmt.Printf("  (all files are automatically considered for commit)\n")
 			fmt.Printf("  (to stop tracking files add rules to '.multignore')\n")
 
-			for _, p := range paths {
-				switch status[p] {
+			if cc.Config.Merge != nil {
+				fmt.Printf("\nMerging %s:\n", cc.Config.Merge.Head.String())
+				fmt.Printf("  (fix conflicts and run 'multi commit')\n")
+
+				for _, p := range cc.Config.Merge.Conflicts {
+					fmt.Printf("\tconflict: %s\n", p)
+				}
+
+				fmt.Println()
+			}
+
+			for _, change := range status {
+				switch change.Type {
 				case dagutils.Add:
-					fmt.Printf("\tnew file: %s\n", p)
+					fmt.Printf("\tnew file: %s\n", change.Path)
 				case dagutils.Remove:
-					fmt.Printf("\tdeleted:  %s\n", p)
+					fmt.Printf("\tdeleted:  %s\n", change.Path)
 				case dagutils.Mod:
-					fmt.Printf("\tmodified: %s\n", p)
+					if err := printModified(c, cc, change); err != nil {
+						return err
+					}
+				case dag.Rename:
+					fmt.Printf("\trenamed:  %s -> %s\n", change.From, change.Path)
+				case dag.Copy:
+					fmt.Printf("\tcopied:   %s -> %s\n", change.From, change.Path)
 				}
 			}
 
@@ -65,3 +76,38 @@ func NewStatusCommand() *cli.Command {
 		},
 	}
 }
+
+// printModified prints the status of a modified file.
+func printModified(c *cli.Context, cc *context.Context, change *dag.Change) error {
+	modeOnly, err := dag.ModeOnly(c.Context, cc.DAG, change)
+	if err != nil {
+		return err
+	}
+
+	if !modeOnly {
+		fmt.Printf("\tmodified: %s\n", change.Path)
+		return nil
+	}
+
+	before, after, err := dag.Modes(c.Context, cc.DAG, change)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("\tmode:     %s (%04o -> %04o)\n", change.Path, before, after)
+	return nil
+}
+
+// addTree adds the working tree to the dag and updates the index.
+func addTree(c *cli.Context, cc *context.Context) (ipld.Node, error) {
+	tree, err := fs.AddIndex(c.Context, cc.DAG, cc.Root, cc.Ignore, cc.Index)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cc.Index.Write(); err != nil {
+		return nil, err
+	}
+
+	return tree, nil
+}
@@ -4,6 +4,8 @@ import (
 	"errors"
 	"os"
 
+	ipld "github.com/ipfs/go-ipld-format"
+	unixfs "github.com/ipfs/go-unixfs"
 	"github.com/urfave/cli/v2"
 
 	"github.com/multiverse-vcs/go-multiverse/pkg/command/context"
@@ -21,6 +23,11 @@ func NewSwitchCommand() *cli.Command {
 				Aliases: []string{"k"},
 				Usage:   "Keep working tree",
 			},
+			&cli.BoolFlag{
+				Name:    "dry-run",
+				Aliases: []string{"n"},
+				Usage:   "Print files that would be removed",
+			},
 		},
 		Action: func(c *cli.Context) error {
 			if c.NArg() != 1 {
@@ -37,6 +44,10 @@ func NewSwitchCommand() *cli.Command {
 				return err
 			}
 
+			if cc.Config.Merge != nil {
+				return errors.New("merge in progress")
+			}
+
 			prev := cc.Config.Branch
 			next := c.Args().Get(0)
 
@@ -49,7 +60,16 @@ func NewSwitchCommand() *cli.Command {
 				return errors.New("branch does not exist")
 			}
 
-			stash, err := fs.Add(c.Context, cc.DAG, cc.Root, context.DefaultIgnore)
+			if c.IsSet("dry-run") {
+				tree, err := stashTree(c, cc, branch)
+				if err != nil {
+					return err
+				}
+
+				return printPrune(c, cc, tree)
+			}
+
+			stash, err := addTree(c, cc)
 			if err != nil {
 				return err
 			}
@@ -61,12 +81,12 @@ func NewSwitchCommand() *cli.Command {
 				return cc.Config.Write()
 			}
 
-			tree, err := cc.DAG.Get(c.Context, branch.Stash)
+			tree, err := stashTree(c, cc, branch)
 			if err != nil {
 				return err
 			}
 
-			if err := fs.Write(c.Context, cc.DAG, cc.Root, tree); err != nil {
+			if err := fs.Checkout(c.Context, cc.DAG, cc.Root, tree, cc.Ignore); err != nil {
 				return err
 			}
 
@@ -74,3 +94,14 @@ func NewSwitchCommand() *cli.Command {
 		},
 	}
 }
+
+// stashTree returns the stashed tree of the branch.
+//
+// Branches without a stash have an empty tree.
+func stashTree(c *cli.Context, cc *context.Context, branch *context.Branch) (ipld.Node, error) {
+	if !branch.Stash.Defined() {
+		return unixfs.EmptyDirNode(), nil
+	}
+
+	return cc.DAG.Get(c.Context, branch.Stash)
+}
@@ -0,0 +1,32 @@
+package command
+
+import (
+	"github.com/libp2p/go-libp2p-core/host"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/urfave/cli/v2"
+
+	"github.com/multiverse-vcs/go-multiverse/internal/p2p"
+	"github.com/multiverse-vcs/go-multiverse/pkg/command/context"
+)
+
+// dialDaemon returns a host that is connected to the local daemon and serves the local dag.
+func dialDaemon(c *cli.Context, cc *context.Context) (host.Host, peer.ID, error) {
+	info, err := context.Daemon()
+	if err != nil {
+		return nil, "", err
+	}
+
+	h, err := p2p.NewClientHost(c.Context)
+	if err != nil {
+		return nil, "", err
+	}
+
+	p2p.ServeSync(h, cc.DAG)
+
+	if err := h.Connect(c.Context, info); err != nil {
+		h.Close()
+		return nil, "", err
+	}
+
+	return h, info.ID, nil
+}
@@ -0,0 +1,100 @@
+package tag
+
+import (
+	"errors"
+	"os"
+
+	"github.com/multiverse-vcs/go-multiverse/pkg/command/context"
+	"github.com/multiverse-vcs/go-multiverse/pkg/object"
+	"github.com/urfave/cli/v2"
+)
+
+// NewCreateCommand returns a new command.
+func NewCreateCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "create",
+		Usage:     "Create a new tag",
+		ArgsUsage: "<name> [branch or commit]",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "message",
+				Aliases: []string{"m"},
+				Usage:   "Description of the tag",
+			},
+			&cli.BoolFlag{
+				Name:    "sign",
+				Aliases: []string{"s"},
+				Usage:   "Sign the tag with the daemon key",
+			},
+			&cli.BoolFlag{
+				Name:    "force",
+				Aliases: []string{"f"},
+				Usage:   "Replace an existing tag",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			if c.NArg() < 1 || c.NArg() > 2 {
+				cli.ShowSubcommandHelpAndExit(c, 1)
+			}
+
+			cwd, err := os.Getwd()
+			if err != nil {
+				return err
+			}
+
+			cc, err := context.New(cwd)
+			if err != nil {
+				return err
+			}
+
+			name := c.Args().Get(0)
+			if _, ok := cc.Config.Tags[name]; ok && !c.Bool("force") {
+				return errors.New("tag already exists")
+			}
+
+			target := cc.Config.Branches[cc.Config.Branch].Head
+			if c.NArg() > 1 {
+				target, err = cc.Config.Ref(c.Args().Get(1))
+				if err != nil {
+					return err
+				}
+			}
+
+			if !target.Defined() {
+				return errors.New("nothing to tag")
+			}
+
+			if _, err := object.GetCommit(c.Context, cc.DAG, target); err != nil {
+				return err
+			}
+
+			identity, err := context.Identity()
+			if err != nil && (c.Bool("sign") || !os.IsNotExist(err)) {
+				return err
+			}
+
+			tag := object.NewTag(target)
+			tag.Message = c.String("message")
+			tag.Tagger = identity
+
+			if c.Bool("sign") {
+				key, err := context.PrivateKey()
+				if err != nil {
+					return err
+				}
+
+				if err := tag.Sign(key); err != nil {
+					return err
+				}
+			}
+
+			id, err := object.AddTag(c.Context, cc.DAG, tag)
+			if err != nil {
+				return err
+			}
+
+			cc.Config.Tags[name] = id
+			return cc.Config.Write()
+		},
+	}
+}
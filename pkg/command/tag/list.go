@@ -0,0 +1,41 @@
+package tag
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/multiverse-vcs/go-multiverse/pkg/command/context"
+	"github.com/urfave/cli/v2"
+)
+
+// NewListCommand returns a new command.
+func NewListCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "list",
+		Usage: "List all tags",
+		Action: func(c *cli.Context) error {
+			cwd, err := os.Getwd()
+			if err != nil {
+				return err
+			}
+
+			cc, err := context.New(cwd)
+			if err != nil {
+				return err
+			}
+
+			var names []string
+			for name := range cc.Config.Tags {
+				names = append(names, name)
+			}
+
+			sort.Strings(names)
+			for _, name := range names {
+				fmt.Println(name)
+			}
+
+			return nil
+		},
+	}
+}
@@ -0,0 +1,69 @@
+package tag
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/multiverse-vcs/go-multiverse/pkg/command/context"
+	"github.com/multiverse-vcs/go-multiverse/pkg/object"
+	"github.com/urfave/cli/v2"
+)
+
+// NewShowCommand returns a new command.
+func NewShowCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "show",
+		Usage: "Print tag info and verify its signature",
+		Action: func(c *cli.Context) error {
+			if c.NArg() != 1 {
+				cli.ShowSubcommandHelpAndExit(c, 1)
+			}
+
+			cwd, err := os.Getwd()
+			if err != nil {
+				return err
+			}
+
+			cc, err := context.New(cwd)
+			if err != nil {
+				return err
+			}
+
+			name := c.Args().Get(0)
+
+			id, ok := cc.Config.Tags[name]
+			if !ok {
+				return errors.New("tag does not exist")
+			}
+
+			tag, err := object.GetTag(c.Context, cc.DAG, id)
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("tag %s\n", name)
+			if tag.Tagger != nil {
+				fmt.Printf("Tagger: %s\n", tag.Tagger.String())
+			}
+			fmt.Printf("Date:   %s\n", tag.Date.Format("Mon Jan 02 15:04:05 2006 -0700"))
+
+			if len(tag.Signature) > 0 {
+				match, err := tag.Verify()
+				if err != nil {
+					return err
+				}
+
+				if !match {
+					return errors.New("bad signature")
+				}
+
+				fmt.Printf("Good signature from %s\n", tag.Tagger.PeerID.Pretty())
+			}
+
+			fmt.Printf("\n\t%s\n\n", tag.Message)
+			fmt.Printf("commit %s\n", tag.Target.String())
+			return nil
+		},
+	}
+}
@@ -0,0 +1,19 @@
+package tag
+
+import (
+	"github.com/urfave/cli/v2"
+)
+
+// NewCommand returns a new command.
+func NewCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "tag",
+		Usage: "List, create, or delete tags",
+		Subcommands: []*cli.Command{
+			NewListCommand(),
+			NewCreateCommand(),
+			NewDeleteCommand(),
+			NewShowCommand(),
+		},
+	}
+}
@@ -1,10 +1,12 @@
 package dag
 
 import (
+	"bufio"
 	"context"
 	"errors"
 	"io"
 
+	blocks "github.com/ipfs/go-block-format"
 	cid "github.com/ipfs/go-cid"
 	blockstore "github.com/ipfs/go-ipfs-blockstore"
 	ipld "github.com/ipfs/go-ipld-format"
@@ -13,9 +15,22 @@ import (
 	"github.com/ipld/go-car/util"
 )
 
+const (
+	// CarBatchSize is the maximum number of blocks stored at once when reading a car.
+	CarBatchSize = 256
+	// CarBatchBytes is the maximum size of blocks stored at once when reading a car.
+	CarBatchBytes = 8 << 20
+)
+
+// Progress is called with the number of blocks and bytes copied so far.
+type Progress func(blocks int, size int64)
+
 type carWriter struct {
-	ds ipld.NodeGetter
-	w  io.Writer
+	ds       ipld.NodeGetter
+	w        *bufio.Writer
+	blocks   int
+	size     int64
+	progress Progress
 }
 
 func (cw *carWriter) getLinks(ctx context.Context, id cid.Cid) ([]*ipld.Link, error) {
@@ -28,54 +43,136 @@ func (cw *carWriter) getLinks(ctx context.Context, id cid.Cid) ([]*ipld.Link, er
 		return nil, err
 	}
 
+	cw.blocks++
+	cw.size += int64(len(node.RawData()))
+
+	if cw.progress != nil {
+		cw.progress(cw.blocks, cw.size)
+	}
+
 	return node.Links(), nil
 }
 
-// WriteCar writes the dag into the given writer starting at head and stopping at refs.
-func WriteCar(ctx context.Context, ds ipld.NodeGetter, head cid.Cid, refs *cid.Set, w io.Writer) error {
+// WriteCar writes the dags starting at roots into the given writer and stopping at refs.
+//
+// Blocks are written as they are visited so the dag is never held in memory.
+// The progress func is optional.
+func WriteCar(ctx context.Context, ds ipld.NodeGetter, roots []cid.Cid, refs *cid.Set, w io.Writer, progress Progress) error {
+	if len(roots) == 0 {
+		return errors.New("car requires at least one root")
+	}
+
 	h := &car.CarHeader{
-		Roots:   []cid.Cid{head},
+		Roots:   roots,
 		Version: 1,
 	}
 
-	if err := car.WriteHeader(h, w); err != nil {
+	cw := carWriter{
+		ds:       ds,
+		w:        bufio.NewWriter(w),
+		progress: progress,
+	}
+
+	if err := car.WriteHeader(h, cw.w); err != nil {
 		return err
 	}
 
-	cw := carWriter{
-		ds: ds,
-		w:  w,
+	for _, root := range roots {
+		if err := merkledag.Walk(ctx, cw.getLinks, root, refs.Visit); err != nil {
+			return err
+		}
 	}
 
-	return merkledag.Walk(ctx, cw.getLinks, head, refs.Visit)
+	return cw.w.Flush()
 }
 
-// ReadCar reads the car into the given dag and returns the root cid.
-func ReadCar(bs blockstore.Blockstore, r io.Reader) (cid.Cid, error) {
-	cr, err := car.NewCarReader(r)
+// ReadCar reads the car into the given blockstore and returns the root cids.
+//
+// Each block is verified against its cid and blocks are stored in
+// bounded batches so that large cars do not exceed transaction limits.
+// The progress func is optional.
+func ReadCar(ctx context.Context, bs blockstore.Blockstore, r io.Reader, progress Progress) ([]cid.Cid, error) {
+	br := bufio.NewReader(r)
+
+	h, err := car.ReadHeader(br)
 	if err != nil {
-		return cid.Cid{}, err
+		return nil, err
+	}
+
+	if h.Version != 1 {
+		return nil, errors.New("unsupported car version")
 	}
 
-	if len(cr.Header.Roots) != 1 {
-		return cid.Cid{}, errors.New("unexpected header roots")
+	if len(h.Roots) == 0 {
+		return nil, errors.New("car has no roots")
 	}
 
-	// load blocks slowly or badger will return an error
+	var batch []blocks.Block
+	var batchSize int
+	var count int
+	var size int64
+
 	for {
-		block, err := cr.Next()
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		id, data, err := util.ReadNode(br)
 		if err == io.EOF {
 			break
 		}
 
 		if err != nil {
-			return cid.Cid{}, err
+			return nil, err
 		}
 
-		if err := bs.Put(block); err != nil {
-			return cid.Cid{}, err
+		block, err := verifyBlock(id, data)
+		if err != nil {
+			return nil, err
 		}
+
+		batch = append(batch, block)
+		batchSize += len(data)
+
+		count++
+		size += int64(len(data))
+
+		if len(batch) < CarBatchSize && batchSize < CarBatchBytes {
+			continue
+		}
+
+		if err := bs.PutMany(batch); err != nil {
+			return nil, err
+		}
+
+		if progress != nil {
+			progress(count, size)
+		}
+
+		batch, batchSize = nil, 0
+	}
+
+	if err := bs.PutMany(batch); err != nil {
+		return nil, err
+	}
+
+	if progress != nil {
+		progress(count, size)
+	}
+
+	return h.Roots, nil
+}
+
+// verifyBlock returns a block if the data matches the hash of the cid.
+func verifyBlock(id cid.Cid, data []byte) (blocks.Block, error) {
+	hash, err := id.Prefix().Sum(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if !hash.Equals(id) {
+		return nil, errors.New("block hash does not match cid")
 	}
 
-	return cr.Header.Roots[0], nil
+	return blocks.NewBlockWithCid(data, id)
 }
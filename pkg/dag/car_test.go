@@ -0,0 +1,105 @@
+package dag
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	cid "github.com/ipfs/go-cid"
+	datastore "github.com/ipfs/go-datastore"
+	dssync "github.com/ipfs/go-datastore/sync"
+	blockstore "github.com/ipfs/go-ipfs-blockstore"
+	ipld "github.com/ipfs/go-ipld-format"
+	merkledag "github.com/ipfs/go-merkledag"
+	"github.com/ipfs/go-merkledag/dagutils"
+)
+
+func TestCar(t *testing.T) {
+	ctx := context.Background()
+	ds := dagutils.NewMemoryDagService()
+
+	skip := merkledag.NodeWithData([]byte("skip"))
+	leaf := merkledag.NodeWithData([]byte("leaf"))
+	root := merkledag.NodeWithData([]byte("root"))
+	other := merkledag.NodeWithData([]byte("other"))
+
+	if err := root.AddNodeLink("leaf", leaf); err != nil {
+		t.Fatal("failed to add link")
+	}
+
+	if err := root.AddNodeLink("skip", skip); err != nil {
+		t.Fatal("failed to add link")
+	}
+
+	if err := ds.AddMany(ctx, []ipld.Node{skip, leaf, root, other}); err != nil {
+		t.Fatal("failed to add nodes")
+	}
+
+	refs := cid.NewSet()
+	refs.Add(skip.Cid())
+
+	var written int
+	progress := func(blocks int, size int64) {
+		written = blocks
+	}
+
+	var buf bytes.Buffer
+	roots := []cid.Cid{root.Cid(), other.Cid()}
+	if err := WriteCar(ctx, ds, roots, refs, &buf, progress); err != nil {
+		t.Fatal("failed to write car")
+	}
+
+	if written != 3 {
+		t.Errorf("expected 3 blocks written but got %d", written)
+	}
+
+	bs := blockstore.NewBlockstore(dssync.MutexWrap(datastore.NewMapDatastore()))
+
+	var read int
+	progress = func(blocks int, size int64) {
+		read = blocks
+	}
+
+	match, err := ReadCar(ctx, bs, &buf, progress)
+	if err != nil {
+		t.Fatal("failed to read car")
+	}
+
+	if len(match) != 2 || match[0] != root.Cid() || match[1] != other.Cid() {
+		t.Error("unexpected car roots")
+	}
+
+	if read != 3 {
+		t.Errorf("expected 3 blocks read but got %d", read)
+	}
+
+	if ok, _ := bs.Has(leaf.Cid()); !ok {
+		t.Error("expected leaf block")
+	}
+
+	if ok, _ := bs.Has(skip.Cid()); ok {
+		t.Error("expected skipped block to be missing")
+	}
+}
+
+func TestReadCarVerify(t *testing.T) {
+	ctx := context.Background()
+	ds := dagutils.NewMemoryDagService()
+
+	node := merkledag.NodeWithData([]byte("original"))
+	if err := ds.Add(ctx, node); err != nil {
+		t.Fatal("failed to add node")
+	}
+
+	var buf bytes.Buffer
+	if err := WriteCar(ctx, ds, []cid.Cid{node.Cid()}, cid.NewSet(), &buf, nil); err != nil {
+		t.Fatal("failed to write car")
+	}
+
+	data := bytes.Replace(buf.Bytes(), []byte("original"), []byte("modified"), 1)
+	bs := blockstore.NewBlockstore(dssync.MutexWrap(datastore.NewMapDatastore()))
+
+	if _, err := ReadCar(ctx, bs, bytes.NewReader(data), nil); err == nil {
+		t.Error("expected modified block to fail verification")
+	}
+}
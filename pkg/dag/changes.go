@@ -0,0 +1,205 @@
+package dag
+
+import (
+	"context"
+	"path"
+	"sort"
+
+	cid "github.com/ipfs/go-cid"
+	ipld "github.com/ipfs/go-ipld-format"
+	"github.com/ipfs/go-merkledag/dagutils"
+	unixfs "github.com/ipfs/go-unixfs"
+	ufsio "github.com/ipfs/go-unixfs/io"
+)
+
+// Changes returns a sorted list of file changes between the trees before and after.
+//
+// Unlike Diff, added or removed directories are expanded into their files.
+// An undefined CID is treated as an empty tree.
+func Changes(ctx context.Context, ds ipld.DAGService, before, after cid.Cid) ([]*dagutils.Change, error) {
+	changes, err := changes(ctx, ds, "", before, after)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+
+	return changes, nil
+}
+
+// changes returns the file changes between a and b at the given path.
+func changes(ctx context.Context, ds ipld.DAGService, p string, a, b cid.Cid) ([]*dagutils.Change, error) {
+	if a == b {
+		return nil, nil
+	}
+
+	linksA, err := links(ctx, ds, a)
+	if err != nil {
+		return nil, err
+	}
+
+	linksB, err := links(ctx, ds, b)
+	if err != nil {
+		return nil, err
+	}
+
+	// compare files directly
+	if linksA == nil && linksB == nil {
+		return fileChange(p, a, b), nil
+	}
+
+	// file replaced by a directory or vice versa
+	if linksA == nil || linksB == nil {
+		return expand(ctx, ds, p, a, b)
+	}
+
+	var out []*dagutils.Change
+	for name, id := range linksA {
+		sub, err := changes(ctx, ds, path.Join(p, name), id, linksB[name])
+		if err != nil {
+			return nil, err
+		}
+
+		out = append(out, sub...)
+	}
+
+	for name, id := range linksB {
+		if _, ok := linksA[name]; ok {
+			continue
+		}
+
+		sub, err := changes(ctx, ds, path.Join(p, name), cid.Cid{}, id)
+		if err != nil {
+			return nil, err
+		}
+
+		out = append(out, sub...)
+	}
+
+	return out, nil
+}
+
+// expand returns the changes between a and b by comparing all files.
+func expand(ctx context.Context, ds ipld.DAGService, p string, a, b cid.Cid) ([]*dagutils.Change, error) {
+	filesA, err := Files(ctx, ds, p, a)
+	if err != nil {
+		return nil, err
+	}
+
+	filesB, err := Files(ctx, ds, p, b)
+	if err != nil {
+		return nil, err
+	}
+
+	var out []*dagutils.Change
+	for name, id := range filesA {
+		out = append(out, fileChange(name, id, filesB[name])...)
+	}
+
+	for name, id := range filesB {
+		if _, ok := filesA[name]; !ok {
+			out = append(out, fileChange(name, cid.Cid{}, id)...)
+		}
+	}
+
+	return out, nil
+}
+
+// fileChange returns the change between files a and b.
+func fileChange(p string, a, b cid.Cid) []*dagutils.Change {
+	switch {
+	case a == b:
+		return nil
+	case !a.Defined():
+		return []*dagutils.Change{{Type: dagutils.Add, Path: p, After: b}}
+	case !b.Defined():
+		return []*dagutils.Change{{Type: dagutils.Remove, Path: p, Before: a}}
+	default:
+		return []*dagutils.Change{{Type: dagutils.Mod, Path: p, Before: a, After: b}}
+	}
+}
+
+// links returns the directory entries of the node with id.
+//
+// A nil map is returned if the node is a file.
+func links(ctx context.Context, ds ipld.DAGService, id cid.Cid) (map[string]cid.Cid, error) {
+	if !id.Defined() {
+		return make(map[string]cid.Cid), nil
+	}
+
+	node, err := ds.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	fsnode, err := unixfs.ExtractFSNode(node)
+	if err != nil {
+		return nil, err
+	}
+
+	if !fsnode.IsDir() {
+		return nil, nil
+	}
+
+	dir, err := ufsio.NewDirectoryFromNode(ds, node)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := dir.Links(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(map[string]cid.Cid)
+	for _, l := range list {
+		out[l.Name] = l.Cid
+	}
+
+	return out, nil
+}
+
+// Files returns a map of file paths to CIDs contained in the node with id.
+func Files(ctx context.Context, ds ipld.DAGService, p string, id cid.Cid) (map[string]cid.Cid, error) {
+	return walkFiles(ctx, ds, p, id, false)
+}
+
+// Entries returns a map of file and empty directory paths to CIDs contained in the node with id.
+func Entries(ctx context.Context, ds ipld.DAGService, p string, id cid.Cid) (map[string]cid.Cid, error) {
+	return walkFiles(ctx, ds, p, id, true)
+}
+
+// walkFiles returns the paths of files below the node with id and optionally empty directories.
+func walkFiles(ctx context.Context, ds ipld.DAGService, p string, id cid.Cid, dirs bool) (map[string]cid.Cid, error) {
+	files := make(map[string]cid.Cid)
+
+	entries, err := links(ctx, ds, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if entries == nil {
+		files[p] = id
+		return files, nil
+	}
+
+	if dirs && len(entries) == 0 && p != "" {
+		files[p] = id
+		return files, nil
+	}
+
+	for name, sub := range entries {
+		subfiles, err := walkFiles(ctx, ds, path.Join(p, name), sub, dirs)
+		if err != nil {
+			return nil, err
+		}
+
+		for k, v := range subfiles {
+			files[k] = v
+		}
+	}
+
+	return files, nil
+}
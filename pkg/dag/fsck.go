@@ -0,0 +1,211 @@
+package dag
+
+import (
+	"context"
+	"fmt"
+
+	blocks "github.com/ipfs/go-block-format"
+	cid "github.com/ipfs/go-cid"
+	blockstore "github.com/ipfs/go-ipfs-blockstore"
+	merkledag "github.com/ipfs/go-merkledag"
+	unixfs "github.com/ipfs/go-unixfs"
+
+	"github.com/multiverse-vcs/go-multiverse/pkg/object"
+)
+
+const (
+	// CommitObject is the type of commit objects.
+	CommitObject = "commit"
+	// TreeObject is the type of unixfs file and directory nodes.
+	TreeObject = "tree"
+	// TagObject is the type of tag objects.
+	TagObject = "tag"
+	// RepositoryObject is the type of repository objects.
+	RepositoryObject = "repository"
+)
+
+// Problem describes a damaged or missing object.
+type Problem struct {
+	// Ref is the name of the reference the object was found from.
+	Ref string `json:"ref"`
+	// ID is the CID of the object.
+	ID cid.Cid `json:"id"`
+	// Type is the expected object type.
+	Type string `json:"type"`
+	// Error describes what is wrong with the object.
+	Error string `json:"error"`
+}
+
+// String returns a human readable description of the problem.
+func (p *Problem) String() string {
+	return fmt.Sprintf("%s %s: %s (%s)", p.Type, p.ID.String(), p.Error, p.Ref)
+}
+
+// Checker verifies the integrity of objects in a blockstore.
+//
+// Objects are read directly from the blockstore so missing
+// blocks are never fetched from the network.
+type Checker struct {
+	// Problems contains all problems found so far.
+	Problems []*Problem
+	// Count is the number of objects checked.
+	Count int
+
+	bs   blockstore.Blockstore
+	seen *cid.Set
+}
+
+// checkItem is an object waiting to be checked.
+type checkItem struct {
+	id  cid.Cid
+	typ string
+}
+
+// NewChecker returns a checker for the given blockstore.
+func NewChecker(bs blockstore.Blockstore) *Checker {
+	return &Checker{
+		bs:   bs,
+		seen: cid.NewSet(),
+	}
+}
+
+// Check verifies the object of the given type and every object it references.
+//
+// Commit parents are followed so the entire history is checked.
+func (c *Checker) Check(ctx context.Context, ref, typ string, id cid.Cid) error {
+	stack := []checkItem{{id, typ}}
+	for len(stack) > 0 {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		item := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		if !item.id.Defined() {
+			c.report(ref, item.typ, item.id, "undefined reference")
+			continue
+		}
+
+		if !c.seen.Visit(item.id) {
+			continue
+		}
+
+		c.Count++
+
+		next, err := c.check(ref, item)
+		if err != nil {
+			return err
+		}
+
+		stack = append(stack, next...)
+	}
+
+	return nil
+}
+
+// check verifies a single object and returns the objects it references.
+func (c *Checker) check(ref string, item checkItem) ([]checkItem, error) {
+	block, err := c.bs.Get(item.id)
+	if err == blockstore.ErrNotFound {
+		c.report(ref, item.typ, item.id, "missing block")
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	hash, err := item.id.Prefix().Sum(block.RawData())
+	if err != nil || !hash.Equals(item.id) {
+		c.report(ref, item.typ, item.id, "block hash does not match cid")
+		return nil, nil
+	}
+
+	next, err := references(item, block)
+	if err != nil {
+		c.report(ref, item.typ, item.id, err.Error())
+		return nil, nil
+	}
+
+	return next, nil
+}
+
+// report adds a problem to the list of problems.
+func (c *Checker) report(ref, typ string, id cid.Cid, msg string) {
+	c.Problems = append(c.Problems, &Problem{
+		Ref:   ref,
+		ID:    id,
+		Type:  typ,
+		Error: msg,
+	})
+}
+
+// references decodes the block as the item type and returns the objects it references.
+func references(item checkItem, block blocks.Block) ([]checkItem, error) {
+	var next []checkItem
+
+	switch item.typ {
+	case CommitObject:
+		commit, err := object.CommitFromCBOR(block.RawData())
+		if err != nil {
+			return nil, err
+		}
+
+		next = append(next, checkItem{commit.Tree, TreeObject})
+		for _, id := range commit.Parents {
+			next = append(next, checkItem{id, CommitObject})
+		}
+	case TagObject:
+		tag, err := object.TagFromCBOR(block.RawData())
+		if err != nil {
+			// imported lightweight tags point directly to commits
+			if _, cerr := object.CommitFromCBOR(block.RawData()); cerr == nil {
+				return references(checkItem{item.id, CommitObject}, block)
+			}
+
+			return nil, err
+		}
+
+		next = append(next, checkItem{tag.Target, CommitObject})
+	case RepositoryObject:
+		repo, err := object.RepositoryFromCBOR(block.RawData())
+		if err != nil {
+			return nil, err
+		}
+
+		for _, id := range repo.Branches {
+			next = append(next, checkItem{id, CommitObject})
+		}
+
+		for _, id := range repo.Tags {
+			next = append(next, checkItem{id, TagObject})
+		}
+	case TreeObject:
+		if item.id.Type() == cid.Raw {
+			return nil, nil
+		}
+
+		node, err := merkledag.DecodeProtobufBlock(block)
+		if err != nil {
+			return nil, err
+		}
+
+		pn, ok := node.(*merkledag.ProtoNode)
+		if !ok {
+			return nil, merkledag.ErrNotProtobuf
+		}
+
+		if _, err := unixfs.FSNodeFromBytes(pn.Data()); err != nil {
+			return nil, err
+		}
+
+		for _, link := range pn.Links() {
+			next = append(next, checkItem{link.Cid, TreeObject})
+		}
+	default:
+		return nil, fmt.Errorf("unknown object type %s", item.typ)
+	}
+
+	return next, nil
+}
@@ -0,0 +1,119 @@
+package dag
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	blocks "github.com/ipfs/go-block-format"
+	blockservice "github.com/ipfs/go-blockservice"
+	cid "github.com/ipfs/go-cid"
+	datastore "github.com/ipfs/go-datastore"
+	dssync "github.com/ipfs/go-datastore/sync"
+	blockstore "github.com/ipfs/go-ipfs-blockstore"
+	offline "github.com/ipfs/go-ipfs-exchange-offline"
+	merkledag "github.com/ipfs/go-merkledag"
+	"github.com/ipfs/go-unixfs"
+
+	"github.com/multiverse-vcs/go-multiverse/pkg/object"
+)
+
+func TestChecker(t *testing.T) {
+	ctx := context.Background()
+
+	bs := blockstore.NewBlockstore(dssync.MutexWrap(datastore.NewMapDatastore()))
+	ds := merkledag.NewDAGService(blockservice.New(bs, offline.Exchange(bs)))
+
+	file, err := Chunk(ctx, ds, strings.NewReader("hello"))
+	if err != nil {
+		t.Fatal("failed to add file")
+	}
+
+	tree := unixfs.EmptyDirNode()
+	if err := tree.AddNodeLink("hello.txt", file); err != nil {
+		t.Fatal("failed to add link")
+	}
+
+	if err := ds.Add(ctx, tree); err != nil {
+		t.Fatal("failed to add tree")
+	}
+
+	parent := object.NewCommit()
+	parent.Tree = tree.Cid()
+
+	parentID, err := object.AddCommit(ctx, ds, parent)
+	if err != nil {
+		t.Fatal("failed to add commit")
+	}
+
+	commit := object.NewCommit()
+	commit.Tree = tree.Cid()
+	commit.Parents = []cid.Cid{parentID}
+
+	commitID, err := object.AddCommit(ctx, ds, commit)
+	if err != nil {
+		t.Fatal("failed to add commit")
+	}
+
+	checker := NewChecker(bs)
+	if err := checker.Check(ctx, "main", CommitObject, commitID); err != nil {
+		t.Fatal("failed to check")
+	}
+
+	if len(checker.Problems) != 0 {
+		t.Fatalf("unexpected problems %v", checker.Problems)
+	}
+
+	if checker.Count != 4 {
+		t.Errorf("expected 4 objects checked but got %d", checker.Count)
+	}
+
+	// replace the file with corrupt data and remove the parent commit
+	corrupt, err := blocks.NewBlockWithCid([]byte("corrupt"), file.Cid())
+	if err != nil {
+		t.Fatal("failed to create block")
+	}
+
+	if err := bs.DeleteBlock(file.Cid()); err != nil {
+		t.Fatal("failed to delete block")
+	}
+
+	if err := bs.Put(corrupt); err != nil {
+		t.Fatal("failed to put block")
+	}
+
+	if err := bs.DeleteBlock(parentID); err != nil {
+		t.Fatal("failed to delete block")
+	}
+
+	checker = NewChecker(bs)
+	if err := checker.Check(ctx, "main", CommitObject, commitID); err != nil {
+		t.Fatal("failed to check")
+	}
+
+	if err := checker.Check(ctx, "stash", CommitObject, tree.Cid()); err != nil {
+		t.Fatal("failed to check")
+	}
+
+	if len(checker.Problems) != 2 {
+		t.Fatalf("expected 2 problems but got %v", checker.Problems)
+	}
+
+	seen := make(map[cid.Cid]bool)
+	for _, p := range checker.Problems {
+		seen[p.ID] = true
+	}
+
+	if !seen[file.Cid()] || !seen[parentID] {
+		t.Error("expected corrupt file and missing commit")
+	}
+
+	checker = NewChecker(bs)
+	if err := checker.Check(ctx, "stash", CommitObject, tree.Cid()); err != nil {
+		t.Fatal("failed to check")
+	}
+
+	if len(checker.Problems) != 1 || checker.Problems[0].ID != tree.Cid() {
+		t.Error("expected tree to be an invalid commit")
+	}
+}
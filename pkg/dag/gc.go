@@ -0,0 +1,74 @@
+package dag
+
+import (
+	"context"
+
+	cid "github.com/ipfs/go-cid"
+	blockstore "github.com/ipfs/go-ipfs-blockstore"
+	ipld "github.com/ipfs/go-ipld-format"
+	merkledag "github.com/ipfs/go-merkledag"
+)
+
+// Reachable returns the set of all CIDs reachable from the given roots.
+func Reachable(ctx context.Context, ds ipld.NodeGetter, roots []cid.Cid) (*cid.Set, error) {
+	getLinks := merkledag.GetLinksWithDAG(ds)
+	marked := cid.NewSet()
+
+	for _, root := range roots {
+		if !root.Defined() {
+			continue
+		}
+
+		if err := merkledag.Walk(ctx, getLinks, root, marked.Visit); err != nil {
+			return nil, err
+		}
+	}
+
+	return marked, nil
+}
+
+// GC removes all blocks that are not reachable from the given roots.
+//
+// It returns the number of blocks and bytes removed. When dry run
+// is set the blocks are counted but not removed.
+func GC(ctx context.Context, bs blockstore.Blockstore, ds ipld.NodeGetter, roots []cid.Cid, dryRun bool) (int, int64, error) {
+	marked, err := Reachable(ctx, ds, roots)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	keys, err := bs.AllKeysChan(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var sweep []cid.Cid
+	for id := range keys {
+		if !marked.Has(id) {
+			sweep = append(sweep, id)
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return 0, 0, err
+	}
+
+	var size int64
+	for _, id := range sweep {
+		n, err := bs.GetSize(id)
+		if err != nil {
+			return 0, 0, err
+		}
+
+		size += int64(n)
+		if dryRun {
+			continue
+		}
+
+		if err := bs.DeleteBlock(id); err != nil {
+			return 0, 0, err
+		}
+	}
+
+	return len(sweep), size, nil
+}
@@ -0,0 +1,63 @@
+package dag
+
+import (
+	"context"
+	"testing"
+
+	blockservice "github.com/ipfs/go-blockservice"
+	cid "github.com/ipfs/go-cid"
+	datastore "github.com/ipfs/go-datastore"
+	dssync "github.com/ipfs/go-datastore/sync"
+	blockstore "github.com/ipfs/go-ipfs-blockstore"
+	offline "github.com/ipfs/go-ipfs-exchange-offline"
+	ipld "github.com/ipfs/go-ipld-format"
+	merkledag "github.com/ipfs/go-merkledag"
+)
+
+func TestGC(t *testing.T) {
+	ctx := context.Background()
+
+	bs := blockstore.NewBlockstore(dssync.MutexWrap(datastore.NewMapDatastore()))
+	ds := merkledag.NewDAGService(blockservice.New(bs, offline.Exchange(bs)))
+
+	leaf := merkledag.NodeWithData([]byte("leaf"))
+	root := merkledag.NodeWithData([]byte("root"))
+	garbage := merkledag.NodeWithData([]byte("garbage"))
+
+	if err := root.AddNodeLink("leaf", leaf); err != nil {
+		t.Fatal("failed to add link")
+	}
+
+	if err := ds.AddMany(ctx, []ipld.Node{leaf, root, garbage}); err != nil {
+		t.Fatal("failed to add nodes")
+	}
+
+	roots := []cid.Cid{root.Cid(), cid.Cid{}}
+
+	count, size, err := GC(ctx, bs, ds, roots, true)
+	if err != nil {
+		t.Fatal("failed to gc")
+	}
+
+	if count != 1 || size != int64(len(garbage.RawData())) {
+		t.Errorf("unexpected dry run result %d %d", count, size)
+	}
+
+	if ok, _ := bs.Has(garbage.Cid()); !ok {
+		t.Fatal("expected dry run to keep blocks")
+	}
+
+	if _, _, err := GC(ctx, bs, ds, roots, false); err != nil {
+		t.Fatal("failed to gc")
+	}
+
+	if ok, _ := bs.Has(garbage.Cid()); ok {
+		t.Error("expected garbage to be removed")
+	}
+
+	for _, id := range []cid.Cid{root.Cid(), leaf.Cid()} {
+		if ok, _ := bs.Has(id); !ok {
+			t.Error("expected reachable block to be kept")
+		}
+	}
+}
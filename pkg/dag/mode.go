@@ -0,0 +1,195 @@
+package dag
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"os"
+
+	cid "github.com/ipfs/go-cid"
+	ipld "github.com/ipfs/go-ipld-format"
+	"github.com/ipfs/go-merkledag"
+)
+
+// DefaultFileMode is the mode of files without mode metadata.
+const DefaultFileMode os.FileMode = 0644
+
+// modeTag is the protobuf key of the UnixFS 1.5 mode field.
+const modeTag = 7<<3 | 0
+
+// Mode returns the unix permission bits of the file node.
+//
+// The mode is stored in the UnixFS 1.5 mode field. Modification times
+// are not stored so that identical contents always have the same CID.
+func Mode(node ipld.Node) (os.FileMode, error) {
+	pn, ok := node.(*merkledag.ProtoNode)
+	if !ok {
+		return DefaultFileMode, nil
+	}
+
+	mode := DefaultFileMode
+	err := scanFields(pn.Data(), func(key, value uint64, raw []byte) {
+		if key == modeTag {
+			mode = os.FileMode(value).Perm()
+		}
+	})
+
+	return mode, err
+}
+
+// SetMode returns a copy of the file node with the given mode and adds it to the dag.
+func SetMode(ctx context.Context, ds ipld.DAGService, node ipld.Node, mode os.FileMode) (ipld.Node, error) {
+	out, err := withMode(node, mode)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ds.Add(ctx, out); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// Modes returns the file modes before and after the change.
+//
+// Zero is returned for sides of the change that do not exist.
+func Modes(ctx context.Context, ds ipld.DAGService, change *Change) (os.FileMode, os.FileMode, error) {
+	before, err := FileMode(ctx, ds, change.Before)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	after, err := FileMode(ctx, ds, change.After)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return before, after, nil
+}
+
+// ModeOnly returns true if the change only modifies the file mode.
+func ModeOnly(ctx context.Context, ds ipld.DAGService, change *Change) (bool, error) {
+	if !change.Before.Defined() || !change.After.Defined() {
+		return false, nil
+	}
+
+	before, err := ds.Get(ctx, change.Before)
+	if err != nil {
+		return false, err
+	}
+
+	after, err := ds.Get(ctx, change.After)
+	if err != nil {
+		return false, err
+	}
+
+	mode, err := Mode(after)
+	if err != nil {
+		return false, err
+	}
+
+	node, err := withMode(before, mode)
+	if err != nil {
+		return false, err
+	}
+
+	return node.Cid() == after.Cid(), nil
+}
+
+// FileMode returns the mode of the file with id or zero if undefined.
+func FileMode(ctx context.Context, ds ipld.DAGService, id cid.Cid) (os.FileMode, error) {
+	if !id.Defined() {
+		return 0, nil
+	}
+
+	node, err := ds.Get(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+
+	return Mode(node)
+}
+
+// withMode returns a copy of the file node with the given mode.
+//
+// The default mode is never stored so that existing CIDs are unchanged.
+func withMode(node ipld.Node, mode os.FileMode) (ipld.Node, error) {
+	pn, ok := node.(*merkledag.ProtoNode)
+	if !ok {
+		return nil, errors.New("invalid file node")
+	}
+
+	var data []byte
+	err := scanFields(pn.Data(), func(key, value uint64, raw []byte) {
+		if key != modeTag {
+			data = append(data, raw...)
+		}
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if mode.Perm() != DefaultFileMode {
+		data = appendUvarint(data, modeTag)
+		data = appendUvarint(data, uint64(mode.Perm()))
+	}
+
+	out := pn.Copy().(*merkledag.ProtoNode)
+	out.SetData(data)
+
+	return out, nil
+}
+
+// scanFields calls fn with the key, varint value, and raw bytes of each protobuf field in data.
+func scanFields(data []byte, fn func(key, value uint64, raw []byte)) error {
+	for len(data) > 0 {
+		key, n := binary.Uvarint(data)
+		if n <= 0 {
+			return errors.New("invalid protobuf key")
+		}
+
+		var value uint64
+		var size int
+
+		switch key & 7 {
+		case 0:
+			v, m := binary.Uvarint(data[n:])
+			if m <= 0 {
+				return errors.New("invalid protobuf varint")
+			}
+
+			value, size = v, n+m
+		case 1:
+			size = n + 8
+		case 2:
+			l, m := binary.Uvarint(data[n:])
+			if m <= 0 || l > uint64(len(data)) {
+				return errors.New("invalid protobuf length")
+			}
+
+			size = n + m + int(l)
+		case 5:
+			size = n + 4
+		default:
+			return errors.New("invalid protobuf wire type")
+		}
+
+		if size > len(data) {
+			return errors.New("invalid protobuf field")
+		}
+
+		fn(key, value, data[:size])
+		data = data[size:]
+	}
+
+	return nil
+}
+
+// appendUvarint appends the varint encoding of v to data.
+func appendUvarint(data []byte, v uint64) []byte {
+	buf := make([]byte, binary.MaxVarintLen64)
+	n := binary.PutUvarint(buf, v)
+	return append(data, buf[:n]...)
+}
@@ -0,0 +1,100 @@
+package dag
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/ipfs/go-merkledag/dagutils"
+	unixfs "github.com/ipfs/go-unixfs"
+)
+
+func TestSetMode(t *testing.T) {
+	ctx := context.Background()
+	mem := dagutils.NewMemoryDagService()
+
+	node, err := Chunk(ctx, mem, strings.NewReader("#!/bin/sh\necho hello\n"))
+	if err != nil {
+		t.Fatal("failed to add file")
+	}
+
+	exec, err := SetMode(ctx, mem, node, 0755)
+	if err != nil {
+		t.Fatal("failed to set mode")
+	}
+
+	if exec.Cid() == node.Cid() {
+		t.Error("expected cid to change")
+	}
+
+	mode, err := Mode(exec)
+	if err != nil {
+		t.Fatal("failed to get mode")
+	}
+
+	if mode != 0755 {
+		t.Error("unexpected mode")
+	}
+
+	fsnode, err := unixfs.ExtractFSNode(exec)
+	if err != nil {
+		t.Fatal("failed to decode unixfs node")
+	}
+
+	if fsnode.Type() != unixfs.TFile {
+		t.Error("unexpected unixfs type")
+	}
+
+	reset, err := SetMode(ctx, mem, exec, DefaultFileMode)
+	if err != nil {
+		t.Fatal("failed to set mode")
+	}
+
+	if reset.Cid() != node.Cid() {
+		t.Error("expected default mode to not be stored")
+	}
+}
+
+func TestModeOnly(t *testing.T) {
+	ctx := context.Background()
+	mem := dagutils.NewMemoryDagService()
+
+	node, err := Chunk(ctx, mem, strings.NewReader("#!/bin/sh\necho hello\n"))
+	if err != nil {
+		t.Fatal("failed to add file")
+	}
+
+	exec, err := SetMode(ctx, mem, node, 0755)
+	if err != nil {
+		t.Fatal("failed to set mode")
+	}
+
+	edit, err := Chunk(ctx, mem, strings.NewReader("#!/bin/sh\necho world\n"))
+	if err != nil {
+		t.Fatal("failed to add file")
+	}
+
+	change := &Change{}
+	change.Before = node.Cid()
+	change.After = exec.Cid()
+
+	modeOnly, err := ModeOnly(ctx, mem, change)
+	if err != nil {
+		t.Fatal("failed to compare modes")
+	}
+
+	if !modeOnly {
+		t.Error("expected mode only change")
+	}
+
+	change.After = edit.Cid()
+
+	modeOnly, err = ModeOnly(ctx, mem, change)
+	if err != nil {
+		t.Fatal("failed to compare modes")
+	}
+
+	if modeOnly {
+		t.Error("expected content change")
+	}
+}
@@ -0,0 +1,305 @@
+package dag
+
+import (
+	"context"
+	"io/ioutil"
+	"path"
+	"sort"
+
+	cid "github.com/ipfs/go-cid"
+	ipld "github.com/ipfs/go-ipld-format"
+	"github.com/ipfs/go-merkledag/dagutils"
+	unixfs "github.com/ipfs/go-unixfs"
+	ufsio "github.com/ipfs/go-unixfs/io"
+
+	"github.com/multiverse-vcs/go-multiverse/pkg/diff"
+)
+
+const (
+	// Rename is the change type of a moved file.
+	Rename dagutils.ChangeType = dagutils.Mod + 1 + iota
+	// Copy is the change type of a copied file.
+	Copy
+)
+
+const (
+	// DefaultSimilarity is the minimum similarity percentage of an edited rename.
+	DefaultSimilarity = 50
+	// RenameLimit is the maximum number of file pairs compared by content.
+	RenameLimit = 1000
+)
+
+// Change contains info about a changed file.
+type Change struct {
+	dagutils.Change
+	// From is the source path of a renamed or copied file.
+	From string
+	// Similarity is the percentage of content shared with the source file.
+	Similarity int
+}
+
+// Renames detects renamed and copied files in the given changes.
+//
+// Files with identical CIDs are always paired. Remaining removed and
+// added files are paired when their contents are similar enough.
+// Copies are detected from exact matches with files in the before tree.
+func Renames(ctx context.Context, ds ipld.DAGService, before cid.Cid, changes []*dagutils.Change) ([]*Change, error) {
+	var removed, added []*dagutils.Change
+	for _, c := range changes {
+		switch c.Type {
+		case dagutils.Remove:
+			removed = append(removed, c)
+		case dagutils.Add:
+			added = append(added, c)
+		}
+	}
+
+	// pairs of added paths to removed changes
+	pairs := make(map[string]*Change)
+	sources := make(map[string]bool)
+
+	// exact renames have matching CIDs and prefer matching names
+	for _, sameName := range []bool{true, false} {
+		for _, a := range added {
+			for _, r := range removed {
+				if pairs[a.Path] != nil || sources[r.Path] || r.Before != a.After {
+					continue
+				}
+
+				if sameName && path.Base(r.Path) != path.Base(a.Path) {
+					continue
+				}
+
+				pairs[a.Path] = rename(r, a, 100)
+				sources[r.Path] = true
+			}
+		}
+	}
+
+	matches, err := similar(ctx, ds, removed, added, pairs, sources)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, m := range matches {
+		pairs[m.Path] = m
+		sources[m.From] = true
+	}
+
+	copied, err := copies(ctx, ds, before, added, pairs)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, c := range copied {
+		pairs[c.Path] = c
+	}
+
+	var out []*Change
+	for _, c := range changes {
+		switch {
+		case c.Type == dagutils.Remove && sources[c.Path]:
+			continue
+		case c.Type == dagutils.Add && pairs[c.Path] != nil:
+			out = append(out, pairs[c.Path])
+		default:
+			out = append(out, &Change{Change: *c})
+		}
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Path < out[j].Path
+	})
+
+	return out, nil
+}
+
+// rename returns a change that moves the file from r to a.
+func rename(r, a *dagutils.Change, similarity int) *Change {
+	return &Change{
+		Change: dagutils.Change{
+			Type:   Rename,
+			Path:   a.Path,
+			Before: r.Before,
+			After:  a.After,
+		},
+		From:       r.Path,
+		Similarity: similarity,
+	}
+}
+
+// similar pairs unmatched removed and added files with similar contents.
+func similar(ctx context.Context, ds ipld.DAGService, removed, added []*dagutils.Change, pairs map[string]*Change, sources map[string]bool) ([]*Change, error) {
+	var srcs, dsts []*dagutils.Change
+	for _, r := range removed {
+		if !sources[r.Path] {
+			srcs = append(srcs, r)
+		}
+	}
+
+	for _, a := range added {
+		if pairs[a.Path] == nil {
+			dsts = append(dsts, a)
+		}
+	}
+
+	if len(srcs) == 0 || len(dsts) == 0 || len(srcs)*len(dsts) > RenameLimit {
+		return nil, nil
+	}
+
+	texts := make(map[cid.Cid]string)
+	for _, c := range append(srcs, dsts...) {
+		id := c.Before
+		if c.Type == dagutils.Add {
+			id = c.After
+		}
+
+		text, ok, err := ReadText(ctx, ds, id)
+		if err != nil {
+			return nil, err
+		}
+
+		if ok {
+			texts[id] = text
+		}
+	}
+
+	var candidates []*Change
+	for _, r := range srcs {
+		textR, okR := texts[r.Before]
+		for _, a := range dsts {
+			textA, okA := texts[a.After]
+			if !okR || !okA {
+				continue
+			}
+
+			score := Similarity(textR, textA)
+			if score >= DefaultSimilarity {
+				candidates = append(candidates, rename(r, a, score))
+			}
+		}
+	}
+
+	// prefer the most similar pairs
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Similarity > candidates[j].Similarity
+	})
+
+	used := make(map[string]bool)
+	var out []*Change
+	for _, c := range candidates {
+		if used[c.From] || used[c.Path] {
+			continue
+		}
+
+		used[c.From] = true
+		used[c.Path] = true
+		out = append(out, c)
+	}
+
+	return out, nil
+}
+
+// copies finds unmatched added files that are exact copies of files in the before tree.
+func copies(ctx context.Context, ds ipld.DAGService, before cid.Cid, added []*dagutils.Change, pairs map[string]*Change) ([]*Change, error) {
+	var dsts []*dagutils.Change
+	for _, a := range added {
+		if pairs[a.Path] == nil {
+			dsts = append(dsts, a)
+		}
+	}
+
+	if len(dsts) == 0 {
+		return nil, nil
+	}
+
+	files, err := Files(ctx, ds, "", before)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make(map[cid.Cid]string)
+	for p, id := range files {
+		if other, ok := paths[id]; !ok || p < other {
+			paths[id] = p
+		}
+	}
+
+	var out []*Change
+	for _, a := range dsts {
+		from, ok := paths[a.After]
+		if !ok {
+			continue
+		}
+
+		out = append(out, &Change{
+			Change: dagutils.Change{
+				Type:   Copy,
+				Path:   a.Path,
+				Before: a.After,
+				After:  a.After,
+			},
+			From:       from,
+			Similarity: 100,
+		})
+	}
+
+	return out, nil
+}
+
+// Similarity returns the percentage of lines shared by a and b.
+func Similarity(a, b string) int {
+	lines := diff.Lines(a, b)
+	if len(lines) == 0 {
+		return 100
+	}
+
+	var equal int
+	for _, l := range lines {
+		if l.Op == diff.Equal {
+			equal++
+		}
+	}
+
+	insert, delete := diff.Stat(lines)
+	total := 2*equal + insert + delete
+
+	return 200 * equal / total
+}
+
+// ReadText returns the contents of the text file with id.
+//
+// False is returned if the file is not a regular text file.
+func ReadText(ctx context.Context, ds ipld.DAGService, id cid.Cid) (string, bool, error) {
+	node, err := ds.Get(ctx, id)
+	if err != nil {
+		return "", false, err
+	}
+
+	fsnode, err := unixfs.ExtractFSNode(node)
+	if err != nil {
+		return "", false, err
+	}
+
+	if fsnode.Type() != unixfs.TFile {
+		return "", false, nil
+	}
+
+	reader, err := ufsio.NewDagReader(ctx, node, ds)
+	if err != nil {
+		return "", false, err
+	}
+	defer reader.Close()
+
+	data, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return "", false, err
+	}
+
+	text := string(data)
+	if diff.IsBinary(text) {
+		return "", false, nil
+	}
+
+	return text, true, nil
+}
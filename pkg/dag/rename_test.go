@@ -0,0 +1,77 @@
+package dag
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	cid "github.com/ipfs/go-cid"
+	"github.com/ipfs/go-merkledag/dagutils"
+)
+
+func TestRenames(t *testing.T) {
+	ctx := context.Background()
+	mem := dagutils.NewMemoryDagService()
+
+	moved, err := Chunk(ctx, mem, strings.NewReader("celery\ngarlic\nonions\n"))
+	if err != nil {
+		t.Fatal("failed to add file")
+	}
+
+	before, err := Chunk(ctx, mem, strings.NewReader("salmon\ntomatoes\nwine\nlemons\n"))
+	if err != nil {
+		t.Fatal("failed to add file")
+	}
+
+	after, err := Chunk(ctx, mem, strings.NewReader("salmon\ntomatoes\nwine\nlimes\n"))
+	if err != nil {
+		t.Fatal("failed to add file")
+	}
+
+	treeA, err := BuildTree(ctx, mem, map[string]cid.Cid{
+		"a.txt": moved.Cid(),
+		"b.txt": before.Cid(),
+	})
+	if err != nil {
+		t.Fatal("failed to build tree")
+	}
+
+	treeB, err := BuildTree(ctx, mem, map[string]cid.Cid{
+		"dir/a.txt": moved.Cid(),
+		"c.txt":     after.Cid(),
+		"d.txt":     moved.Cid(),
+	})
+	if err != nil {
+		t.Fatal("failed to build tree")
+	}
+
+	changes, err := Changes(ctx, mem, treeA.Cid(), treeB.Cid())
+	if err != nil {
+		t.Fatal("failed to get changes")
+	}
+
+	renames, err := Renames(ctx, mem, treeA.Cid(), changes)
+	if err != nil {
+		t.Fatal("failed to detect renames")
+	}
+
+	if len(renames) != 3 {
+		t.Fatalf("unexpected changes %d", len(renames))
+	}
+
+	if renames[0].Type != Rename || renames[0].From != "b.txt" || renames[0].Path != "c.txt" {
+		t.Error("expected similar rename")
+	}
+
+	if renames[0].Similarity != 75 {
+		t.Error("unexpected similarity")
+	}
+
+	if renames[1].Type != Copy || renames[1].From != "a.txt" || renames[1].Path != "d.txt" {
+		t.Error("expected exact copy")
+	}
+
+	if renames[2].Type != Rename || renames[2].From != "a.txt" || renames[2].Path != "dir/a.txt" {
+		t.Error("expected exact rename")
+	}
+}
@@ -5,28 +5,28 @@ import (
 
 	cid "github.com/ipfs/go-cid"
 	ipld "github.com/ipfs/go-ipld-format"
-	merkledag "github.com/ipfs/go-merkledag"
 	"github.com/ipfs/go-merkledag/dagutils"
 	"github.com/multiverse-vcs/go-multiverse/pkg/object"
 )
 
-// Status returns the changes between the given tree and commit with id.
-func Status(ctx context.Context, ds ipld.DAGService, tree ipld.Node, id cid.Cid) (map[string]dagutils.ChangeType, error) {
-	if !id.Defined() {
-		return Diff(ctx, ds, &merkledag.ProtoNode{}, tree)
-	}
+// Status returns the file changes between the given tree and commit with id.
+func Status(ctx context.Context, ds ipld.DAGService, tree ipld.Node, id cid.Cid) ([]*Change, error) {
+	var before cid.Cid
+	if id.Defined() {
+		commit, err := object.GetCommit(ctx, ds, id)
+		if err != nil {
+			return nil, err
+		}
 
-	commit, err := object.GetCommit(ctx, ds, id)
-	if err != nil {
-		return nil, err
+		before = commit.Tree
 	}
 
-	index, err := ds.Get(ctx, commit.Tree)
+	changes, err := Changes(ctx, ds, before, tree.Cid())
 	if err != nil {
 		return nil, err
 	}
 
-	return Diff(ctx, ds, index, tree)
+	return Renames(ctx, ds, before, changes)
 }
 
 // Diff returns a flattened map of changes between before and after.
@@ -0,0 +1,62 @@
+package dag
+
+import (
+	"context"
+	"strings"
+
+	cid "github.com/ipfs/go-cid"
+	ipld "github.com/ipfs/go-ipld-format"
+	ufsio "github.com/ipfs/go-unixfs/io"
+)
+
+// BuildTree creates a directory tree from a map of file paths to CIDs.
+//
+// Paths may also refer to empty directories. A directory that also
+// contains other paths is replaced by the directory built from them.
+func BuildTree(ctx context.Context, ds ipld.DAGService, files map[string]cid.Cid) (ipld.Node, error) {
+	dir := ufsio.NewDirectory(ds)
+	subdirs := make(map[string]map[string]cid.Cid)
+
+	for p, id := range files {
+		parts := strings.SplitN(p, "/", 2)
+		if len(parts) == 2 {
+			if subdirs[parts[0]] == nil {
+				subdirs[parts[0]] = make(map[string]cid.Cid)
+			}
+
+			subdirs[parts[0]][parts[1]] = id
+			continue
+		}
+
+		node, err := ds.Get(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := dir.AddChild(ctx, p, node); err != nil {
+			return nil, err
+		}
+	}
+
+	for name, subfiles := range subdirs {
+		node, err := BuildTree(ctx, ds, subfiles)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := dir.AddChild(ctx, name, node); err != nil {
+			return nil, err
+		}
+	}
+
+	node, err := dir.GetNode()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ds.Add(ctx, node); err != nil {
+		return nil, err
+	}
+
+	return node, nil
+}
@@ -0,0 +1,218 @@
+// Package diff contains methods for comparing text line by line.
+package diff
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/sergi/go-diff/diffmatchpatch"
+)
+
+const (
+	// DefaultContext is the number of unchanged lines surrounding a hunk.
+	DefaultContext = 3
+	// BinarySniffLen is the number of bytes checked when detecting binary files.
+	BinarySniffLen = 8000
+)
+
+// Op is the type of line edit.
+type Op int
+
+const (
+	// Equal means the line is unchanged.
+	Equal Op = iota
+	// Insert means the line was added.
+	Insert
+	// Delete means the line was removed.
+	Delete
+)
+
+// Line is a single line of a diff.
+type Line struct {
+	// Op is the type of edit.
+	Op Op
+	// Text is the line contents including the newline.
+	Text string
+}
+
+// Hunk is a group of nearby line edits.
+type Hunk struct {
+	// StartA is the first line number of the hunk in a.
+	StartA int
+	// LenA is the number of lines of the hunk in a.
+	LenA int
+	// StartB is the first line number of the hunk in b.
+	StartB int
+	// LenB is the number of lines of the hunk in b.
+	LenB int
+	// Lines contains the hunk lines.
+	Lines []Line
+}
+
+// Lines returns the line edits needed to turn a into b.
+func Lines(a, b string) []Line {
+	dmp := diffmatchpatch.New()
+	runesA, runesB, lines := dmp.DiffLinesToRunes(a, b)
+	diffs := dmp.DiffMainRunes(runesA, runesB, false)
+
+	var out []Line
+	for _, d := range diffs {
+		op := Equal
+		switch d.Type {
+		case diffmatchpatch.DiffInsert:
+			op = Insert
+		case diffmatchpatch.DiffDelete:
+			op = Delete
+		}
+
+		for _, r := range d.Text {
+			out = append(out, Line{Op: op, Text: lines[r]})
+		}
+	}
+
+	return out
+}
+
+// Hunks groups the line edits into hunks with the given lines of context.
+func Hunks(lines []Line, context int) []*Hunk {
+	// line offsets in a and b before each index
+	posA := make([]int, len(lines)+1)
+	posB := make([]int, len(lines)+1)
+
+	for i, l := range lines {
+		posA[i+1], posB[i+1] = posA[i], posB[i]
+		if l.Op != Insert {
+			posA[i+1]++
+		}
+		if l.Op != Delete {
+			posB[i+1]++
+		}
+	}
+
+	var hunks []*Hunk
+	start, end := -1, -1
+
+	flush := func() {
+		hunk := &Hunk{
+			StartA: posA[start],
+			LenA:   posA[end] - posA[start],
+			StartB: posB[start],
+			LenB:   posB[end] - posB[start],
+			Lines:  lines[start:end],
+		}
+
+		// line numbers are one based unless the range is empty
+		if hunk.LenA > 0 {
+			hunk.StartA++
+		}
+		if hunk.LenB > 0 {
+			hunk.StartB++
+		}
+
+		hunks = append(hunks, hunk)
+	}
+
+	for i, l := range lines {
+		if l.Op == Equal {
+			continue
+		}
+
+		lo, hi := i-context, i+context+1
+		if lo < 0 {
+			lo = 0
+		}
+		if hi > len(lines) {
+			hi = len(lines)
+		}
+
+		if start >= 0 && lo <= end {
+			end = hi
+			continue
+		}
+
+		if start >= 0 {
+			flush()
+		}
+
+		start, end = lo, hi
+	}
+
+	if start >= 0 {
+		flush()
+	}
+
+	return hunks
+}
+
+// Stat returns the number of inserted and deleted lines.
+func Stat(lines []Line) (insert int, delete int) {
+	for _, l := range lines {
+		switch l.Op {
+		case Insert:
+			insert++
+		case Delete:
+			delete++
+		}
+	}
+
+	return insert, delete
+}
+
+// Unified writes the differences between a and b to w in unified format.
+func Unified(w io.Writer, nameA, nameB, a, b string) error {
+	hunks := Hunks(Lines(a, b), DefaultContext)
+	if len(hunks) == 0 {
+		return nil
+	}
+
+	if _, err := fmt.Fprintf(w, "--- %s\n+++ %s\n", nameA, nameB); err != nil {
+		return err
+	}
+
+	for _, h := range hunks {
+		if _, err := fmt.Fprintf(w, "@@ -%d,%d +%d,%d @@\n", h.StartA, h.LenA, h.StartB, h.LenB); err != nil {
+			return err
+		}
+
+		for _, l := range h.Lines {
+			if err := writeLine(w, l); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// IsBinary returns true if the text looks like binary data.
+func IsBinary(text string) bool {
+	data := []byte(text)
+	if len(data) > BinarySniffLen {
+		data = data[:BinarySniffLen]
+	}
+
+	return bytes.IndexByte(data, 0) >= 0
+}
+
+// writeLine writes a single prefixed diff line.
+func writeLine(w io.Writer, l Line) error {
+	prefix := " "
+	switch l.Op {
+	case Insert:
+		prefix = "+"
+	case Delete:
+		prefix = "-"
+	}
+
+	if _, err := fmt.Fprint(w, prefix, l.Text); err != nil {
+		return err
+	}
+
+	if len(l.Text) > 0 && l.Text[len(l.Text)-1] == '\n' {
+		return nil
+	}
+
+	_, err := fmt.Fprint(w, "\n\\ No newline at end of file\n")
+	return err
+}
@@ -0,0 +1,92 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+)
+
+const textA = `celery
+garlic
+onions
+salmon
+tomatoes
+wine
+`
+
+const textB = `celery
+salmon
+tomatoes
+garlic
+onions
+wine
+`
+
+const unified = `--- a/list.txt
++++ b/list.txt
+@@ -1,6 +1,6 @@
+ celery
++salmon
++tomatoes
+ garlic
+ onions
+-salmon
+-tomatoes
+ wine
+`
+
+func TestUnified(t *testing.T) {
+	var out strings.Builder
+	if err := Unified(&out, "a/list.txt", "b/list.txt", textA, textB); err != nil {
+		t.Fatal("failed to write diff")
+	}
+
+	if out.String() != unified {
+		t.Errorf("unexpected diff output\n%s", out.String())
+	}
+}
+
+func TestUnifiedNoChanges(t *testing.T) {
+	var out strings.Builder
+	if err := Unified(&out, "a/list.txt", "b/list.txt", textA, textA); err != nil {
+		t.Fatal("failed to write diff")
+	}
+
+	if out.Len() != 0 {
+		t.Error("expected empty diff")
+	}
+}
+
+func TestHunksContext(t *testing.T) {
+	a := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n"
+	b := "1\nx\n3\n4\n5\n6\n7\n8\n9\n10\ny\n12\n"
+
+	hunks := Hunks(Lines(a, b), DefaultContext)
+	if len(hunks) != 2 {
+		t.Fatal("expected two hunks")
+	}
+
+	if hunks[0].StartA != 1 || hunks[0].LenA != 5 {
+		t.Error("unexpected first hunk range")
+	}
+
+	if hunks[1].StartA != 8 || hunks[1].LenA != 5 {
+		t.Error("unexpected second hunk range")
+	}
+}
+
+func TestStat(t *testing.T) {
+	insert, delete := Stat(Lines(textA, textB))
+	if insert != 2 || delete != 2 {
+		t.Error("unexpected stat")
+	}
+}
+
+func TestIsBinary(t *testing.T) {
+	if IsBinary(textA) {
+		t.Error("expected text")
+	}
+
+	if !IsBinary("\x00\x01\x02") {
+		t.Error("expected binary")
+	}
+}
@@ -17,6 +17,13 @@ import (
 
 // Add creates a node from the file at path and adds it to the merkle dag.
 func Add(ctx context.Context, ds ipld.DAGService, path string, filter ignore.Filter) (ipld.Node, error) {
+	return AddIndex(ctx, ds, path, filter, nil)
+}
+
+// AddIndex is like Add but reuses the nodes of files that are unchanged in the index.
+//
+// The index is updated with the stat info of all added files.
+func AddIndex(ctx context.Context, ds ipld.DAGService, path string, filter ignore.Filter, index *Index) (ipld.Node, error) {
 	stat, err := os.Lstat(path)
 	if err != nil {
 		return nil, err
@@ -24,25 +31,56 @@ func Add(ctx context.Context, ds ipld.DAGService, path string, filter ignore.Fil
 
 	switch mode := stat.Mode(); {
 	case mode.IsRegular():
-		return addFile(ctx, ds, path)
+		return addFile(ctx, ds, path, stat, index)
 	case mode&os.ModeSymlink != 0:
 		return addSymlink(ctx, ds, path)
 	case mode.IsDir():
-		return addDir(ctx, ds, path, filter)
+		return addDir(ctx, ds, path, filter, index)
 	default:
 		return nil, errors.New("invalid file type")
 	}
 }
 
 // addFile creates a dag node from the file at the given path.
-func addFile(ctx context.Context, ds ipld.DAGService, path string) (ipld.Node, error) {
+func addFile(ctx context.Context, ds ipld.DAGService, path string, stat os.FileInfo, index *Index) (ipld.Node, error) {
+	if index == nil {
+		return chunkFile(ctx, ds, path, stat.Mode())
+	}
+
+	if id, ok := index.Get(path, stat); ok {
+		if node, err := ds.Get(ctx, id); err == nil {
+			return node, nil
+		}
+	}
+
+	node, err := chunkFile(ctx, ds, path, stat.Mode())
+	if err != nil {
+		return nil, err
+	}
+
+	index.Set(path, stat, node.Cid())
+	return node, nil
+}
+
+// chunkFile creates a dag node from the contents and mode of the file at the given path.
+func chunkFile(ctx context.Context, ds ipld.DAGService, path string, mode os.FileMode) (ipld.Node, error) {
 	file, err := os.Open(path)
 	if err != nil {
 		return nil, err
 	}
 	defer file.Close()
 
-	return dag.Chunk(ctx, ds, file)
+	node, err := dag.Chunk(ctx, ds, file)
+	if err != nil {
+		return nil, err
+	}
+
+	// only the executable bit is tracked like in git
+	if mode&0111 == 0 {
+		return node, nil
+	}
+
+	return dag.SetMode(ctx, ds, node, 0755)
 }
 
 // addSymlink creates a dag node from the symlink at the given path.
@@ -66,7 +104,7 @@ func addSymlink(ctx context.Context, ds ipld.DAGService, path string) (ipld.Node
 }
 
 // addDir creates a dag node from the directory entries at the given path.
-func addDir(ctx context.Context, ds ipld.DAGService, path string, filter ignore.Filter) (ipld.Node, error) {
+func addDir(ctx context.Context, ds ipld.DAGService, path string, filter ignore.Filter, index *Index) (ipld.Node, error) {
 	entries, err := os.ReadDir(path)
 	if err != nil {
 		return nil, err
@@ -82,11 +120,11 @@ func addDir(ctx context.Context, ds ipld.DAGService, path string, filter ignore.
 
 	for _, info := range entries {
 		subpath := filepath.Join(path, info.Name())
-		if filter.Match(subpath) {
+		if filter.Match(subpath, info.IsDir()) {
 			continue
 		}
 
-		subnode, err := Add(ctx, ds, subpath, filter)
+		subnode, err := AddIndex(ctx, ds, subpath, filter, index)
 		if err != nil {
 			return nil, err
 		}
@@ -5,11 +5,14 @@ import (
 	"context"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/ipfs/go-merkledag/dagutils"
 	unixfs "github.com/ipfs/go-unixfs"
 	"github.com/ipfs/go-unixfs/io"
+
+	"github.com/multiverse-vcs/go-multiverse/pkg/dag"
 )
 
 func TestAddFile(t *testing.T) {
@@ -41,6 +44,49 @@ func TestAddFile(t *testing.T) {
 	}
 }
 
+func TestAddFileMode(t *testing.T) {
+	ctx := context.Background()
+	ds := dagutils.NewMemoryDagService()
+
+	dir, err := ioutil.TempDir("", "multi-*")
+	if err != nil {
+		t.Fatal("failed to create temp dir")
+	}
+	defer os.RemoveAll(dir)
+
+	tests := map[os.FileMode]os.FileMode{
+		0600: dag.DefaultFileMode,
+		0664: dag.DefaultFileMode,
+		0700: 0755,
+		0775: 0755,
+	}
+
+	for perm, expect := range tests {
+		path := filepath.Join(dir, perm.String())
+		if err := ioutil.WriteFile(path, []byte("hello"), perm); err != nil {
+			t.Fatal("failed to write file")
+		}
+
+		if err := os.Chmod(path, perm); err != nil {
+			t.Fatal("failed to chmod file")
+		}
+
+		node, err := Add(ctx, ds, path, nil)
+		if err != nil {
+			t.Fatal("failed to add file")
+		}
+
+		mode, err := dag.Mode(node)
+		if err != nil {
+			t.Fatal("failed to get mode")
+		}
+
+		if mode != expect {
+			t.Errorf("expected mode %v for %v got %v", expect, perm, mode)
+		}
+	}
+}
+
 func TestAddDir(t *testing.T) {
 	ctx := context.Background()
 	dag := dagutils.NewMemoryDagService()
@@ -0,0 +1,118 @@
+package fs
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	ipld "github.com/ipfs/go-ipld-format"
+	unixfs "github.com/ipfs/go-unixfs"
+	"github.com/ipfs/go-unixfs/io"
+
+	"github.com/multiverse-vcs/go-multiverse/internal/ignore"
+)
+
+// Checkout writes the contents of node to the path and removes files that are not in the node.
+//
+// Files matching the ignore filter are never removed.
+func Checkout(ctx context.Context, dag ipld.DAGService, path string, node ipld.Node, filter ignore.Filter) error {
+	if _, err := Prune(ctx, dag, path, node, filter, false); err != nil {
+		return err
+	}
+
+	return Write(ctx, dag, path, node)
+}
+
+// Prune returns the paths of files under path that do not exist in node.
+//
+// Entries with a different file type than in node are also included.
+// Unless dryRun is set the files are removed. Ignored files are skipped.
+func Prune(ctx context.Context, dag ipld.DAGService, path string, node ipld.Node, filter ignore.Filter, dryRun bool) ([]string, error) {
+	var removed []string
+
+	visit := func(subpath string) error {
+		rel, err := filepath.Rel(path, subpath)
+		if err != nil {
+			return err
+		}
+
+		removed = append(removed, rel)
+		if dryRun {
+			return nil
+		}
+
+		return os.RemoveAll(subpath)
+	}
+
+	if err := pruneDir(ctx, dag, path, node, filter, visit); err != nil {
+		return nil, err
+	}
+
+	return removed, nil
+}
+
+// pruneDir calls visit for each entry in the directory at path that does not match node.
+func pruneDir(ctx context.Context, dag ipld.DAGService, path string, node ipld.Node, filter ignore.Filter, visit func(string) error) error {
+	entries, err := os.ReadDir(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	other, err := ignore.Load(path)
+	if err != nil {
+		return err
+	}
+
+	filter = filter.Merge(other)
+
+	dir, err := io.NewDirectoryFromNode(dag, node)
+	if err != nil {
+		return err
+	}
+
+	for _, info := range entries {
+		subpath := filepath.Join(path, info.Name())
+		if filter.Match(subpath, info.IsDir()) {
+			continue
+		}
+
+		subnode, err := dir.Find(ctx, info.Name())
+		if err == os.ErrNotExist {
+			if err := visit(subpath); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if err != nil {
+			return err
+		}
+
+		fsnode, err := unixfs.ExtractFSNode(subnode)
+		if err != nil {
+			return err
+		}
+
+		switch mode := info.Type(); {
+		case mode.IsDir() && fsnode.IsDir():
+			err = pruneDir(ctx, dag, subpath, subnode, filter, visit)
+		case mode.IsRegular() && fsnode.Type() == unixfs.TFile:
+			continue
+		case mode&os.ModeSymlink != 0 && fsnode.Type() == unixfs.TSymlink:
+			continue
+		default:
+			err = visit(subpath)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,123 @@
+package fs
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ipfs/go-merkledag/dagutils"
+
+	"github.com/multiverse-vcs/go-multiverse/internal/ignore"
+)
+
+func TestCheckout(t *testing.T) {
+	ctx := context.Background()
+	dag := dagutils.NewMemoryDagService()
+
+	tmp, err := ioutil.TempDir("", "unixfs-*")
+	if err != nil {
+		t.Fatal("failed to create temp dir")
+	}
+	defer os.RemoveAll(tmp)
+
+	node, err := Add(ctx, dag, "testdata", nil)
+	if err != nil {
+		t.Fatal("failed to add file")
+	}
+
+	if err := Write(ctx, dag, tmp, node); err != nil {
+		t.Fatal("failed to write node")
+	}
+
+	if err := os.WriteFile(filepath.Join(tmp, "stale.txt"), []byte("stale"), 0644); err != nil {
+		t.Fatal("failed to write file")
+	}
+
+	if err := os.MkdirAll(filepath.Join(tmp, "c", "d"), 0755); err != nil {
+		t.Fatal("failed to create dir")
+	}
+
+	if err := os.WriteFile(filepath.Join(tmp, "keep.exe"), []byte("keep"), 0644); err != nil {
+		t.Fatal("failed to write file")
+	}
+
+	filter := ignore.New("", "*.exe")
+
+	removed, err := Prune(ctx, dag, tmp, node, filter, true)
+	if err != nil {
+		t.Fatal("failed to prune")
+	}
+
+	if len(removed) != 2 {
+		t.Fatal("unexpected removed files")
+	}
+
+	if removed[0] != "c" || removed[1] != "stale.txt" {
+		t.Error("unexpected removed files")
+	}
+
+	if _, err := os.Stat(filepath.Join(tmp, "stale.txt")); err != nil {
+		t.Error("dry run removed file")
+	}
+
+	if err := Checkout(ctx, dag, tmp, node, filter); err != nil {
+		t.Fatal("failed to checkout")
+	}
+
+	if _, err := os.Stat(filepath.Join(tmp, "stale.txt")); !os.IsNotExist(err) {
+		t.Error("expected file to be removed")
+	}
+
+	if _, err := os.Stat(filepath.Join(tmp, "c")); !os.IsNotExist(err) {
+		t.Error("expected dir to be removed")
+	}
+
+	if _, err := os.Stat(filepath.Join(tmp, "keep.exe")); err != nil {
+		t.Error("expected ignored file to be kept")
+	}
+
+	if _, err := os.Lstat(filepath.Join(tmp, "l")); err != nil {
+		t.Error("expected symlink to be kept")
+	}
+}
+
+func TestCheckoutReplaceType(t *testing.T) {
+	ctx := context.Background()
+	dag := dagutils.NewMemoryDagService()
+
+	tmp, err := ioutil.TempDir("", "unixfs-*")
+	if err != nil {
+		t.Fatal("failed to create temp dir")
+	}
+	defer os.RemoveAll(tmp)
+
+	node, err := Add(ctx, dag, "testdata", nil)
+	if err != nil {
+		t.Fatal("failed to add file")
+	}
+
+	// a.txt is a file in the tree and b is a dir
+	if err := os.MkdirAll(filepath.Join(tmp, "a.txt"), 0755); err != nil {
+		t.Fatal("failed to create dir")
+	}
+
+	if err := os.WriteFile(filepath.Join(tmp, "b"), []byte("b"), 0644); err != nil {
+		t.Fatal("failed to write file")
+	}
+
+	if err := Checkout(ctx, dag, tmp, node, nil); err != nil {
+		t.Fatal("failed to checkout")
+	}
+
+	info, err := os.Stat(filepath.Join(tmp, "a.txt"))
+	if err != nil || info.IsDir() {
+		t.Error("expected file to replace dir")
+	}
+
+	info, err = os.Stat(filepath.Join(tmp, "b"))
+	if err != nil || !info.IsDir() {
+		t.Error("expected dir to replace file")
+	}
+}
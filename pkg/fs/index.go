@@ -0,0 +1,126 @@
+package fs
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	cid "github.com/ipfs/go-cid"
+)
+
+// RacyDuration is the time after a file modification during which it is not indexed.
+//
+// Files modified within the same timestamp granularity as the index
+// could change again without their stat info changing.
+const RacyDuration = 2 * time.Second
+
+// IndexEntry contains the stat info of a previously added file.
+type IndexEntry struct {
+	// Size is the size of the file in bytes.
+	Size int64 `json:"size"`
+	// ModTime is the modification time in unix nanoseconds.
+	ModTime int64 `json:"mtime"`
+	// Inode is the inode number of the file.
+	Inode uint64 `json:"inode"`
+	// Mode is the file mode.
+	Mode os.FileMode `json:"mode"`
+	// ID is the CID of the file node.
+	ID cid.Cid `json:"id"`
+}
+
+// Index is a cache of file stat info to previously added nodes.
+type Index struct {
+	// Entries contains entries keyed by path relative to the root.
+	Entries map[string]*IndexEntry `json:"entries"`
+
+	root string
+	path string
+	seen map[string]bool
+}
+
+// NewIndex returns an empty index for the files under root.
+func NewIndex(root, path string) *Index {
+	return &Index{
+		Entries: make(map[string]*IndexEntry),
+		root:    root,
+		path:    path,
+		seen:    make(map[string]bool),
+	}
+}
+
+// Read reads the index from the path.
+//
+// A missing index file is not an error.
+func (i *Index) Read() error {
+	data, err := os.ReadFile(i.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, i)
+}
+
+// Write writes the entries used since the index was read to the path.
+func (i *Index) Write() error {
+	for p := range i.Entries {
+		if !i.seen[p] {
+			delete(i.Entries, p)
+		}
+	}
+
+	data, err := json.Marshal(i)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(i.path, data, 0644)
+}
+
+// Get returns the CID of the file at path if its stat info is unchanged.
+func (i *Index) Get(path string, info os.FileInfo) (cid.Cid, bool) {
+	key, err := filepath.Rel(i.root, path)
+	if err != nil {
+		return cid.Cid{}, false
+	}
+
+	entry, ok := i.Entries[key]
+	if !ok || *entry != *newIndexEntry(info, entry.ID) {
+		return cid.Cid{}, false
+	}
+
+	i.seen[key] = true
+	return entry.ID, true
+}
+
+// Set updates the entry of the file at path.
+//
+// Files that were modified too recently are not added.
+func (i *Index) Set(path string, info os.FileInfo, id cid.Cid) {
+	key, err := filepath.Rel(i.root, path)
+	if err != nil {
+		return
+	}
+
+	if time.Since(info.ModTime()) < RacyDuration {
+		return
+	}
+
+	i.Entries[key] = newIndexEntry(info, id)
+	i.seen[key] = true
+}
+
+// newIndexEntry returns an entry from the file stat info.
+func newIndexEntry(info os.FileInfo, id cid.Cid) *IndexEntry {
+	return &IndexEntry{
+		Size:    info.Size(),
+		ModTime: info.ModTime().UnixNano(),
+		Inode:   inode(info),
+		Mode:    info.Mode(),
+		ID:      id,
+	}
+}
@@ -0,0 +1,89 @@
+package fs
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ipfs/go-merkledag/dagutils"
+)
+
+func TestAddIndex(t *testing.T) {
+	ctx := context.Background()
+	dag := dagutils.NewMemoryDagService()
+
+	tmp, err := ioutil.TempDir("", "unixfs-*")
+	if err != nil {
+		t.Fatal("failed to create temp dir")
+	}
+	defer os.RemoveAll(tmp)
+
+	path := filepath.Join(tmp, "a.txt")
+	if err := ioutil.WriteFile(path, []byte("hello"), 0644); err != nil {
+		t.Fatal("failed to write file")
+	}
+
+	// make the file old enough to be indexed
+	past := time.Now().Add(-time.Minute)
+	if err := os.Chtimes(path, past, past); err != nil {
+		t.Fatal("failed to change file times")
+	}
+
+	index := NewIndex(tmp, filepath.Join(tmp, "index.json"))
+
+	node, err := AddIndex(ctx, dag, tmp, nil, index)
+	if err != nil {
+		t.Fatal("failed to add dir")
+	}
+
+	if len(index.Entries) != 1 {
+		t.Fatal("expected index entry")
+	}
+
+	if err := index.Write(); err != nil {
+		t.Fatal("failed to write index")
+	}
+
+	other := NewIndex(tmp, filepath.Join(tmp, "index.json"))
+	if err := other.Read(); err != nil {
+		t.Fatal("failed to read index")
+	}
+
+	if other.Entries["a.txt"] == nil {
+		t.Fatal("expected index entry")
+	}
+
+	stat, err := os.Stat(path)
+	if err != nil {
+		t.Fatal("failed to stat file")
+	}
+
+	if _, ok := other.Get(path, stat); !ok {
+		t.Error("expected index hit")
+	}
+
+	if err := ioutil.WriteFile(path, []byte("hello world"), 0644); err != nil {
+		t.Fatal("failed to write file")
+	}
+
+	stat, err = os.Stat(path)
+	if err != nil {
+		t.Fatal("failed to stat file")
+	}
+
+	if _, ok := other.Get(path, stat); ok {
+		t.Error("expected index miss")
+	}
+
+	changed, err := AddIndex(ctx, dag, tmp, nil, other)
+	if err != nil {
+		t.Fatal("failed to add dir")
+	}
+
+	if changed.Cid() == node.Cid() {
+		t.Error("expected changed file to be added")
+	}
+}
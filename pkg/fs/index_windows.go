@@ -0,0 +1,13 @@
+//go:build windows
+// +build windows
+
+package fs
+
+import (
+	"os"
+)
+
+// inode returns zero because file indexes are not available from stat info.
+func inode(info os.FileInfo) uint64 {
+	return 0
+}
@@ -9,6 +9,8 @@ import (
 	ipld "github.com/ipfs/go-ipld-format"
 	unixfs "github.com/ipfs/go-unixfs"
 	"github.com/ipfs/go-unixfs/io"
+
+	"github.com/multiverse-vcs/go-multiverse/pkg/dag"
 )
 
 // Write writes the contents of node to the path.
@@ -24,15 +26,24 @@ func Write(ctx context.Context, dag ipld.DAGService, path string, node ipld.Node
 	case unixfs.TDirectory:
 		return writeDir(ctx, dag, path, node)
 	case unixfs.TSymlink:
-		return os.Symlink(string(fsnode.Data()), path)
+		return writeSymlink(string(fsnode.Data()), path)
 	default:
 		return errors.New("invalid file type")
 	}
 }
 
-// writeSymlink writes the file to the given path.
-func writeFile(ctx context.Context, dag ipld.DAGService, path string, node ipld.Node) error {
-	reader, err := io.NewDagReader(ctx, node, dag)
+// writeSymlink replaces the file at the given path with a symlink to target.
+func writeSymlink(target, path string) error {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return os.Symlink(target, path)
+}
+
+// writeFile writes the file to the given path.
+func writeFile(ctx context.Context, ds ipld.DAGService, path string, node ipld.Node) error {
+	reader, err := io.NewDagReader(ctx, node, ds)
 	if err != nil {
 		return err
 	}
@@ -48,7 +59,12 @@ func writeFile(ctx context.Context, dag ipld.DAGService, path string, node ipld.
 		return err
 	}
 
-	return nil
+	mode, err := dag.Mode(node)
+	if err != nil {
+		return err
+	}
+
+	return file.Chmod(mode)
 }
 
 // writeSymlink writes the directory entries to the given path.
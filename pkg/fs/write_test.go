@@ -123,3 +123,45 @@ func TestWriteDir(t *testing.T) {
 		t.Error("unexpected dir entry")
 	}
 }
+
+func TestWriteMode(t *testing.T) {
+	ctx := context.Background()
+	dag := dagutils.NewMemoryDagService()
+
+	tmp, err := ioutil.TempDir("", "unixfs-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir")
+	}
+	defer os.RemoveAll(tmp)
+
+	path := filepath.Join(tmp, "run.sh")
+	if err := ioutil.WriteFile(path, []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatalf("failed to write file")
+	}
+
+	if err := os.Chmod(path, 0755); err != nil {
+		t.Fatalf("failed to chmod file")
+	}
+
+	node, err := Add(ctx, dag, path, nil)
+	if err != nil {
+		t.Fatalf("failed to add file")
+	}
+
+	if err := os.Chmod(path, 0644); err != nil {
+		t.Fatalf("failed to chmod file")
+	}
+
+	if err := Write(ctx, dag, path, node); err != nil {
+		t.Fatalf("failed to write node")
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("failed to stat file")
+	}
+
+	if info.Mode().Perm() != 0755 {
+		t.Errorf("file mode does not match")
+	}
+}
@@ -0,0 +1,45 @@
+package merge
+
+import (
+	ipld "github.com/ipfs/go-ipld-format"
+)
+
+// Hunk is a region of a file that was changed differently on both sides.
+type Hunk struct {
+	// A contains the lines from the local side.
+	A string `json:"a"`
+	// B contains the lines from the remote side.
+	B string `json:"b"`
+}
+
+// Conflict contains info about a file that could not be merged.
+type Conflict struct {
+	// Path is the file path relative to the tree root.
+	Path string `json:"path"`
+	// Binary indicates the file contents could not be merged.
+	Binary bool `json:"binary"`
+	// Removed indicates the file was removed on one side and changed on the other.
+	Removed bool `json:"removed"`
+	// Renamed indicates the file was renamed differently on both sides.
+	Renamed bool `json:"renamed"`
+	// Hunks contains the conflicting regions of a text file.
+	Hunks []*Hunk `json:"hunks"`
+}
+
+// Result contains the outcome of a merge.
+type Result struct {
+	// Tree is the merged tree.
+	Tree ipld.Node
+	// Conflicts contains files that could not be merged cleanly.
+	Conflicts []*Conflict
+}
+
+// Paths returns the paths of all conflicting files.
+func (r *Result) Paths() []string {
+	var paths []string
+	for _, c := range r.Conflicts {
+		paths = append(paths, c.Path)
+	}
+
+	return paths
+}
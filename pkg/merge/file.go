@@ -7,29 +7,127 @@ import (
 	"github.com/ipfs/go-cid"
 	ipld "github.com/ipfs/go-ipld-format"
 	"github.com/multiverse-vcs/go-multiverse/pkg/dag"
-	"github.com/multiverse-vcs/go-multiverse/pkg/fs"
 	"github.com/nasdf/diff3"
 )
 
 // File combines the contents of two edited files into the original.
-func File(ctx context.Context, ds ipld.DAGService, o, a, b cid.Cid) (ipld.Node, error) {
-	textO, err := fs.Cat(ctx, ds, o)
+//
+// If the edits overlap the returned conflict describes the conflicting hunks.
+// Binary files and symlinks are never merged and the contents of a are kept instead.
+func File(ctx context.Context, ds ipld.DAGService, o, a, b cid.Cid) (ipld.Node, *Conflict, error) {
+	// files added on both sides have no original
+	textO, okO := "", true
+	if o.Defined() {
+		var err error
+		if textO, okO, err = dag.ReadText(ctx, ds, o); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	textA, okA, err := dag.ReadText(ctx, ds, a)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	textB, okB, err := dag.ReadText(ctx, ds, b)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !okO || !okA || !okB {
+		node, err := ds.Get(ctx, a)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return node, &Conflict{Binary: true}, nil
+	}
+
+	merged := diff3.Merge(textO, textA, textB)
+	reader := strings.NewReader(merged)
+
+	node, err := dag.Chunk(ctx, ds, reader)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if node, err = mergeMode(ctx, ds, node, o, a, b); err != nil {
+		return nil, nil, err
+	}
+
+	hunks := parseHunks(merged)
+	if len(hunks) == 0 {
+		return node, nil, nil
+	}
+
+	return node, &Conflict{Hunks: hunks}, nil
+}
+
+// parseHunks returns the conflicting hunks from the merged text.
+func parseHunks(merged string) []*Hunk {
+	var hunks []*Hunk
+	var hunk *Hunk
+	var side *strings.Builder
+
+	for _, line := range strings.SplitAfter(merged, "\n") {
+		switch strings.TrimRight(line, "\r\n") {
+		case diff3.Sep1:
+			hunk = &Hunk{}
+			side = &strings.Builder{}
+			continue
+		case diff3.Sep2:
+			if hunk != nil {
+				hunk.A = side.String()
+				side = &strings.Builder{}
+				continue
+			}
+		case diff3.Sep3:
+			if hunk != nil {
+				hunk.B = side.String()
+				hunks = append(hunks, hunk)
+				hunk, side = nil, nil
+				continue
+			}
+		}
+
+		if side != nil {
+			side.WriteString(line)
+		}
+	}
+
+	return hunks
+}
+
+// mergeMode sets the mode of the merged node to the mode changed by either side.
+func mergeMode(ctx context.Context, ds ipld.DAGService, node ipld.Node, o, a, b cid.Cid) (ipld.Node, error) {
+	modeO, err := dag.FileMode(ctx, ds, o)
 	if err != nil {
 		return nil, err
 	}
 
-	textA, err := fs.Cat(ctx, ds, a)
+	modeA, err := dag.FileMode(ctx, ds, a)
 	if err != nil {
 		return nil, err
 	}
 
-	textB, err := fs.Cat(ctx, ds, b)
+	modeB, err := dag.FileMode(ctx, ds, b)
 	if err != nil {
 		return nil, err
 	}
 
-	merged := diff3.Merge(textO, textA, textB)
-	reader := strings.NewReader(merged)
+	// files added on both sides have no original mode
+	if !o.Defined() {
+		modeO = dag.DefaultFileMode
+	}
+
+	mode := modeA
+	if modeA == modeO {
+		mode = modeB
+	}
+
+	if mode == dag.DefaultFileMode {
+		return node, nil
+	}
 
-	return dag.Chunk(ctx, ds, reader)
+	return dag.SetMode(ctx, ds, node, mode)
 }
@@ -5,10 +5,12 @@ import (
 	"context"
 	"io"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/ipfs/go-merkledag/dagutils"
 	ufsio "github.com/ipfs/go-unixfs/io"
+	"github.com/multiverse-vcs/go-multiverse/pkg/dag"
 	"github.com/multiverse-vcs/go-multiverse/pkg/fs"
 )
 
@@ -31,11 +33,23 @@ func TestFile(t *testing.T) {
 		t.Fatal("failed to add file")
 	}
 
-	merge, err := File(ctx, mem, nodeO.Cid(), nodeA.Cid(), nodeB.Cid())
+	merge, conflict, err := File(ctx, mem, nodeO.Cid(), nodeA.Cid(), nodeB.Cid())
 	if err != nil {
 		t.Fatal("failed to merge")
 	}
 
+	if conflict == nil || len(conflict.Hunks) != 1 {
+		t.Fatal("expected conflict hunk")
+	}
+
+	if conflict.Hunks[0].A != "onions\n" {
+		t.Error("unexpected conflict hunk")
+	}
+
+	if conflict.Hunks[0].B != "salmon\ntomatoes\nonions\n" {
+		t.Error("unexpected conflict hunk")
+	}
+
 	r, err := ufsio.NewDagReader(ctx, merge, mem)
 	if err != nil {
 		t.Fatal("failed to read node")
@@ -59,3 +73,36 @@ func TestFile(t *testing.T) {
 		t.Error("unexpected merge result")
 	}
 }
+
+func TestFileBinary(t *testing.T) {
+	ctx := context.Background()
+	mem := dagutils.NewMemoryDagService()
+
+	nodeO, err := dag.Chunk(ctx, mem, strings.NewReader("\x00original"))
+	if err != nil {
+		t.Fatal("failed to add file")
+	}
+
+	nodeA, err := dag.Chunk(ctx, mem, strings.NewReader("\x00local"))
+	if err != nil {
+		t.Fatal("failed to add file")
+	}
+
+	nodeB, err := dag.Chunk(ctx, mem, strings.NewReader("\x00remote"))
+	if err != nil {
+		t.Fatal("failed to add file")
+	}
+
+	merge, conflict, err := File(ctx, mem, nodeO.Cid(), nodeA.Cid(), nodeB.Cid())
+	if err != nil {
+		t.Fatal("failed to merge")
+	}
+
+	if conflict == nil || !conflict.Binary {
+		t.Error("expected binary conflict")
+	}
+
+	if merge.Cid() != nodeA.Cid() {
+		t.Error("expected local file to be kept")
+	}
+}
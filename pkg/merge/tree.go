@@ -2,94 +2,224 @@ package merge
 
 import (
 	"context"
-	"errors"
+	"sort"
 
 	cid "github.com/ipfs/go-cid"
 	ipld "github.com/ipfs/go-ipld-format"
-	merkledag "github.com/ipfs/go-merkledag"
-	"github.com/ipfs/go-merkledag/dagutils"
+
+	"github.com/multiverse-vcs/go-multiverse/pkg/dag"
 	"github.com/multiverse-vcs/go-multiverse/pkg/object"
 )
 
 // Tree combines the changes to trees a and b onto the base o.
-func Tree(ctx context.Context, ds ipld.DAGService, o, a, b cid.Cid) (ipld.Node, error) {
+//
+// Renamed files are followed so that edits made on one side
+// are carried over to the new location from the other side.
+func Tree(ctx context.Context, ds ipld.DAGService, o, a, b cid.Cid) (*Result, error) {
 	// fast forward b
 	if o == a {
-		return object.GetCommitTree(ctx, ds, b)
+		return fastForward(ctx, ds, b)
 	}
 
 	// fast forward a
 	if o == b {
-		return object.GetCommitTree(ctx, ds, a)
+		return fastForward(ctx, ds, a)
+	}
+
+	commitO, err := object.GetCommit(ctx, ds, o)
+	if err != nil {
+		return nil, err
+	}
+
+	commitA, err := object.GetCommit(ctx, ds, a)
+	if err != nil {
+		return nil, err
+	}
+
+	commitB, err := object.GetCommit(ctx, ds, b)
+	if err != nil {
+		return nil, err
 	}
 
-	treeO, err := object.GetCommitTree(ctx, ds, o)
+	filesO, err := dag.Entries(ctx, ds, "", commitO.Tree)
 	if err != nil {
 		return nil, err
 	}
 
-	treeA, err := object.GetCommitTree(ctx, ds, a)
+	filesA, err := dag.Entries(ctx, ds, "", commitA.Tree)
 	if err != nil {
 		return nil, err
 	}
 
-	treeB, err := object.GetCommitTree(ctx, ds, b)
+	filesB, err := dag.Entries(ctx, ds, "", commitB.Tree)
 	if err != nil {
 		return nil, err
 	}
 
-	changesA, err := dagutils.Diff(ctx, ds, treeO, treeA)
+	renamesA, err := renames(ctx, ds, commitO.Tree, commitA.Tree)
 	if err != nil {
 		return nil, err
 	}
 
-	changesB, err := dagutils.Diff(ctx, ds, treeO, treeB)
+	renamesB, err := renames(ctx, ds, commitO.Tree, commitB.Tree)
 	if err != nil {
 		return nil, err
 	}
 
-	changes, conflicts := dagutils.MergeDiffs(changesA, changesB)
-	for _, c := range conflicts {
-		change, err := resolve(ctx, ds, c)
+	var result Result
+	merged := make(map[string]cid.Cid)
+	seenA := make(map[string]bool)
+	seenB := make(map[string]bool)
+
+	conflict := func(c *Conflict) {
+		if c != nil {
+			result.Conflicts = append(result.Conflicts, c)
+		}
+	}
+
+	// merge files from the base following renames
+	for p, idO := range filesO {
+		pathA, pathB := p, p
+		if to, ok := renamesA[p]; ok {
+			pathA = to
+		}
+		if to, ok := renamesB[p]; ok {
+			pathB = to
+		}
+
+		seenA[pathA] = true
+		seenB[pathB] = true
+
+		dest := pathA
+		if pathA == p {
+			dest = pathB
+		}
+
+		if pathA != p && pathB != p && pathA != pathB {
+			conflict(&Conflict{Path: dest, Renamed: true})
+		}
+
+		id, c, err := mergeFile(ctx, ds, dest, idO, filesA[pathA], filesB[pathB])
 		if err != nil {
 			return nil, err
 		}
 
-		changes = append(changes, change)
+		conflict(c)
+		if id.Defined() {
+			merged[dest] = id
+		}
 	}
 
-	proto, ok := treeO.(*merkledag.ProtoNode)
-	if !ok {
-		return nil, errors.New("invalid tree")
+	// merge files added in a with files added in b
+	for p, idA := range filesA {
+		if seenA[p] {
+			continue
+		}
+
+		if _, ok := merged[p]; ok {
+			conflict(&Conflict{Path: p})
+			continue
+		}
+
+		var idB cid.Cid
+		if !seenB[p] {
+			idB = filesB[p]
+			seenB[p] = true
+		}
+
+		id, c, err := mergeFile(ctx, ds, p, cid.Cid{}, idA, idB)
+		if err != nil {
+			return nil, err
+		}
+
+		conflict(c)
+		merged[p] = id
+	}
+
+	// files added only in b
+	for p, idB := range filesB {
+		if seenB[p] {
+			continue
+		}
+
+		if _, ok := merged[p]; ok {
+			conflict(&Conflict{Path: p})
+			continue
+		}
+
+		merged[p] = idB
+	}
+
+	sort.Slice(result.Conflicts, func(i, j int) bool {
+		return result.Conflicts[i].Path < result.Conflicts[j].Path
+	})
+
+	tree, err := dag.BuildTree(ctx, ds, merged)
+	if err != nil {
+		return nil, err
 	}
 
-	return dagutils.ApplyChange(ctx, ds, proto, changes)
+	result.Tree = tree
+	return &result, nil
 }
 
-// resolve merges the contents of two conflicting dag changes.
-func resolve(ctx context.Context, ds ipld.DAGService, c dagutils.Conflict) (*dagutils.Change, error) {
-	if c.A.Type == dagutils.Remove {
-		return c.B, nil
+// fastForward returns a result containing the tree of the commit with id.
+func fastForward(ctx context.Context, ds ipld.DAGService, id cid.Cid) (*Result, error) {
+	tree, err := object.GetCommitTree(ctx, ds, id)
+	if err != nil {
+		return nil, err
 	}
 
-	if c.B.Type == dagutils.Remove {
-		return c.A, nil
+	return &Result{Tree: tree}, nil
+}
+
+// renames returns a map of original paths to new paths of files renamed between o and a.
+func renames(ctx context.Context, ds ipld.DAGService, o, a cid.Cid) (map[string]string, error) {
+	changes, err := dag.Changes(ctx, ds, o, a)
+	if err != nil {
+		return nil, err
 	}
 
-	merge, err := File(ctx, ds, c.A.Before, c.A.After, c.B.After)
+	list, err := dag.Renames(ctx, ds, o, changes)
 	if err != nil {
 		return nil, err
 	}
 
-	change := dagutils.Mod
-	if c.A.Type == dagutils.Add && c.B.Type == dagutils.Add {
-		change = dagutils.Add
+	out := make(map[string]string)
+	for _, c := range list {
+		if c.Type == dag.Rename {
+			out[c.From] = c.Path
+		}
+	}
+
+	return out, nil
+}
+
+// mergeFile combines the changes to files a and b onto the base o.
+//
+// Undefined CIDs represent files that do not exist.
+func mergeFile(ctx context.Context, ds ipld.DAGService, p string, o, a, b cid.Cid) (cid.Cid, *Conflict, error) {
+	switch {
+	case a == b:
+		return a, nil, nil
+	case a == o:
+		return b, nil, nil
+	case b == o:
+		return a, nil, nil
+	case !a.Defined():
+		return b, &Conflict{Path: p, Removed: true}, nil
+	case !b.Defined():
+		return a, &Conflict{Path: p, Removed: true}, nil
+	}
+
+	node, conflict, err := File(ctx, ds, o, a, b)
+	if err != nil {
+		return cid.Cid{}, nil, err
+	}
+
+	if conflict != nil {
+		conflict.Path = p
 	}
 
-	return &dagutils.Change{
-		Type:   change,
-		Path:   c.A.Path,
-		Before: c.A.Before,
-		After:  merge.Cid(),
-	}, nil
+	return node.Cid(), conflict, nil
 }
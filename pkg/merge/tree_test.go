@@ -5,10 +5,13 @@ import (
 	"context"
 	"io"
 	"os"
+	"strings"
 	"testing"
 
+	cid "github.com/ipfs/go-cid"
 	"github.com/ipfs/go-merkledag/dagutils"
 	ufsio "github.com/ipfs/go-unixfs/io"
+	"github.com/multiverse-vcs/go-multiverse/pkg/dag"
 	"github.com/multiverse-vcs/go-multiverse/pkg/fs"
 	"github.com/multiverse-vcs/go-multiverse/pkg/object"
 )
@@ -61,7 +64,15 @@ func TestTree(t *testing.T) {
 		t.Fatalf("failed to merge %s", err)
 	}
 
-	ufsdir, err := ufsio.NewDirectoryFromNode(mem, merge)
+	if len(merge.Conflicts) != 1 {
+		t.Fatal("expected merge conflict")
+	}
+
+	if merge.Conflicts[0].Path != "list.txt" {
+		t.Error("unexpected conflict path")
+	}
+
+	ufsdir, err := ufsio.NewDirectoryFromNode(mem, merge.Tree)
 	if err != nil {
 		t.Fatal("failed to read node")
 	}
@@ -94,3 +105,137 @@ func TestTree(t *testing.T) {
 		t.Error("unexpected merge result")
 	}
 }
+
+func TestTreeRename(t *testing.T) {
+	ctx := context.Background()
+	mem := dagutils.NewMemoryDagService()
+
+	original, err := dag.Chunk(ctx, mem, strings.NewReader("celery\ngarlic\nonions\n"))
+	if err != nil {
+		t.Fatal("failed to add file")
+	}
+
+	edited, err := dag.Chunk(ctx, mem, strings.NewReader("celery\ngarlic\nonions\nwine\n"))
+	if err != nil {
+		t.Fatal("failed to add file")
+	}
+
+	commit := func(files map[string]cid.Cid) cid.Cid {
+		tree, err := dag.BuildTree(ctx, mem, files)
+		if err != nil {
+			t.Fatal("failed to build tree")
+		}
+
+		c := object.NewCommit()
+		c.Tree = tree.Cid()
+
+		id, err := object.AddCommit(ctx, mem, c)
+		if err != nil {
+			t.Fatal("failed to add commit")
+		}
+
+		return id
+	}
+
+	o := commit(map[string]cid.Cid{"list.txt": original.Cid()})
+	a := commit(map[string]cid.Cid{"dir/items.txt": original.Cid()})
+	b := commit(map[string]cid.Cid{"list.txt": edited.Cid()})
+
+	merge, err := Tree(ctx, mem, o, a, b)
+	if err != nil {
+		t.Fatalf("failed to merge %s", err)
+	}
+
+	if len(merge.Conflicts) != 0 {
+		t.Fatal("unexpected merge conflicts")
+	}
+
+	files, err := dag.Files(ctx, mem, "", merge.Tree.Cid())
+	if err != nil {
+		t.Fatal("failed to list files")
+	}
+
+	if len(files) != 1 {
+		t.Fatal("unexpected merged files")
+	}
+
+	if files["dir/items.txt"] != edited.Cid() {
+		t.Error("expected edits to follow rename")
+	}
+}
+
+func TestTreeEmptyDir(t *testing.T) {
+	ctx := context.Background()
+	mem := dagutils.NewMemoryDagService()
+
+	empty := ufsio.NewDirectory(mem)
+	emptyNode, err := empty.GetNode()
+	if err != nil {
+		t.Fatal("failed to create dir")
+	}
+
+	if err := mem.Add(ctx, emptyNode); err != nil {
+		t.Fatal("failed to add dir")
+	}
+
+	original, err := dag.Chunk(ctx, mem, strings.NewReader("celery\n"))
+	if err != nil {
+		t.Fatal("failed to add file")
+	}
+
+	edited, err := dag.Chunk(ctx, mem, strings.NewReader("garlic\n"))
+	if err != nil {
+		t.Fatal("failed to add file")
+	}
+
+	commit := func(files map[string]cid.Cid) cid.Cid {
+		tree, err := dag.BuildTree(ctx, mem, files)
+		if err != nil {
+			t.Fatal("failed to build tree")
+		}
+
+		c := object.NewCommit()
+		c.Tree = tree.Cid()
+
+		id, err := object.AddCommit(ctx, mem, c)
+		if err != nil {
+			t.Fatal("failed to add commit")
+		}
+
+		return id
+	}
+
+	o := commit(map[string]cid.Cid{"a.txt": original.Cid(), "keep": emptyNode.Cid(), "fill": emptyNode.Cid()})
+	a := commit(map[string]cid.Cid{"a.txt": edited.Cid(), "keep": emptyNode.Cid(), "fill": emptyNode.Cid()})
+	b := commit(map[string]cid.Cid{"a.txt": original.Cid(), "keep": emptyNode.Cid(), "fill/b.txt": original.Cid()})
+
+	merge, err := Tree(ctx, mem, o, a, b)
+	if err != nil {
+		t.Fatalf("failed to merge %s", err)
+	}
+
+	if len(merge.Conflicts) != 0 {
+		t.Fatal("unexpected merge conflicts")
+	}
+
+	entries, err := dag.Entries(ctx, mem, "", merge.Tree.Cid())
+	if err != nil {
+		t.Fatal("failed to list entries")
+	}
+
+	if entries["keep"] != emptyNode.Cid() {
+		t.Error("expected empty dir to be kept")
+	}
+
+	if entries["fill/b.txt"] != original.Cid() {
+		t.Error("expected file added to empty dir")
+	}
+
+	if entries["a.txt"] != edited.Cid() {
+		t.Error("expected edited file")
+	}
+
+	if len(entries) != 3 {
+		t.Errorf("unexpected merged entries %v", entries)
+	}
+}
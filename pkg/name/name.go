@@ -2,7 +2,9 @@ package name
 
 import (
 	"context"
+	"log"
 	"path"
+	"time"
 
 	cid "github.com/ipfs/go-cid"
 	datastore "github.com/ipfs/go-datastore"
@@ -15,12 +17,20 @@ import (
 	namesys "github.com/libp2p/go-libp2p-pubsub-router"
 )
 
-// Namespace is the pubsub topic namespace.
-const Namespace = "multiverse"
+const (
+	// Namespace is the pubsub topic and dht record namespace.
+	Namespace = "multiverse"
+	// SearchTimeout is the maximum time to search for a record.
+	SearchTimeout = time.Minute
+	// PutTimeout is the maximum time to put a record in the dht.
+	PutTimeout = time.Minute
+)
 
 // System performs name resolution.
 type System struct {
 	values *namesys.PubsubValueStore
+	router routing.ValueStore
+	store  *store
 }
 
 // TopicForPeerID returns the topic name for the given peer id.
@@ -29,11 +39,12 @@ func TopicForPeerID(id peer.ID) string {
 }
 
 // NewNameSystem returns a new name system.
-func NewSystem(ctx context.Context, host host.Host, router routing.Routing, dstore datastore.Datastore) (*System, error) {
+//
+// The content router is used to discover pubsub peers and the names
+// value store is searched when no pubsub peer has the record.
+func NewSystem(ctx context.Context, host host.Host, router routing.ContentRouting, names routing.ValueStore, dstore datastore.Datastore) (*System, error) {
 	dis := discovery.NewRoutingDiscovery(router)
 
-	// TODO use datastore to persist values
-
 	sub, err := pubsub.NewGossipSub(ctx, host, pubsub.WithDiscovery(dis))
 	if err != nil {
 		return nil, err
@@ -44,44 +55,161 @@ func NewSystem(ctx context.Context, host host.Host, router routing.Routing, dsto
 		return nil, err
 	}
 
-	return &System{
+	sys := &System{
 		values: values,
-	}, nil
+		router: names,
+		store:  newStore(dstore),
+	}
+
+	if err := sys.load(ctx); err != nil {
+		return nil, err
+	}
+
+	go sys.persist(ctx)
+	return sys, nil
+}
+
+// load adds the stored records to the value store so they can be served to peers.
+func (s *System) load(ctx context.Context) error {
+	values, err := s.store.All()
+	if err != nil {
+		return err
+	}
+
+	for key, val := range values {
+		// expired records are replaced when the author republishes
+		if err := s.store.validator.Validate(key, val); err != nil {
+			continue
+		}
+
+		if err := s.values.PutValue(ctx, key, val); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// persist periodically saves the latest records of all subscribed topics.
+func (s *System) persist(ctx context.Context) {
+	ticker := time.NewTicker(StoreInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		for _, key := range s.values.GetSubscriptions() {
+			val, err := s.values.GetValue(ctx, key)
+			if err != nil {
+				continue
+			}
+
+			s.store.Put(key, val)
+		}
+	}
 }
 
 // GetValue returns the latest value for the topic with the given peer id.
 func (s *System) GetValue(ctx context.Context, id peer.ID) (*Record, error) {
-	val, err := s.values.GetValue(ctx, TopicForPeerID(id))
+	key := TopicForPeerID(id)
+
+	val, err := s.values.GetValue(ctx, key)
 	if err != nil {
 		return nil, err
 	}
 
+	if err := s.store.Put(key, val); err != nil {
+		return nil, err
+	}
+
 	return RecordFromCBOR(val)
 }
 
 // PutValue publishes the value under the topic of the given peer id.
 func (s *System) PutValue(ctx context.Context, id peer.ID, rec *Record) error {
+	key := TopicForPeerID(id)
+
 	val, err := rec.Bytes()
 	if err != nil {
 		return err
 	}
 
-	return s.values.PutValue(ctx, TopicForPeerID(id), val)
+	if err := s.values.PutValue(ctx, key, val); err != nil {
+		return err
+	}
+
+	// the dht put is best effort because the routing table may be empty
+	go s.putRouter(key, val)
+
+	return s.store.Put(key, val)
 }
 
-// Search searches for the the latest value from the topic with the given peer ID.
+// putRouter publishes the value to the dht without blocking the caller.
+//
+// A new context is used because the caller may be cancelled before the put completes.
+func (s *System) putRouter(key string, val []byte) {
+	ctx, cancel := context.WithTimeout(context.Background(), PutTimeout)
+	defer cancel()
+
+	if err := s.router.PutValue(ctx, key, val); err != nil {
+		log.Printf("failed to put name record in dht: %v", err)
+	}
+}
+
+// SearchValue searches pubsub and the dht for the latest value from the topic with the given peer ID.
+//
+// The first valid result from either source wins and is compared with the stored record.
 func (s *System) SearchValue(ctx context.Context, id peer.ID) (*Record, error) {
-	out, err := s.values.SearchValue(ctx, TopicForPeerID(id))
+	key := TopicForPeerID(id)
+
+	ctx, cancel := context.WithTimeout(ctx, SearchTimeout)
+	defer cancel()
+
+	pubsubOut, err := s.values.SearchValue(ctx, key)
 	if err != nil {
 		return nil, err
 	}
 
-	val, ok := <-out
-	if !ok {
-		return nil, routing.ErrNotFound
+	dhtOut, err := s.router.SearchValue(ctx, key)
+	if err != nil {
+		return nil, err
 	}
 
-	return RecordFromCBOR(val)
+	for pubsubOut != nil || dhtOut != nil {
+		var val []byte
+		var ok bool
+
+		select {
+		case val, ok = <-pubsubOut:
+			if !ok {
+				pubsubOut = nil
+				continue
+			}
+		case val, ok = <-dhtOut:
+			if !ok {
+				dhtOut = nil
+				continue
+			}
+		}
+
+		if err := s.store.Put(key, val); err != nil {
+			continue
+		}
+
+		// the stored record wins over older values even if it has expired
+		rec, err := s.store.Record(key)
+		if err != nil || rec.Expired() {
+			continue
+		}
+
+		return rec, nil
+	}
+
+	return nil, routing.ErrNotFound
 }
 
 // Subscribe creates a subscription to the topic of the given peer ID.
@@ -95,20 +223,23 @@ func (s *System) Unsubscribe(id peer.ID) (bool, error) {
 }
 
 // Publish advertises the given id to the topic of the peer ID from the private key.
+//
+// The sequence number continues from the last stored record even if it has expired.
 func (s *System) Publish(ctx context.Context, key crypto.PrivKey, id cid.Cid) error {
 	peerID, err := peer.IDFromPrivateKey(key)
 	if err != nil {
 		return err
 	}
 
-	val, err := s.GetValue(ctx, peerID)
-	if err != nil && err != routing.ErrNotFound {
+	rec := NewRecord(id.Bytes())
+
+	prev, err := s.store.Record(TopicForPeerID(peerID))
+	if err != nil && err != datastore.ErrNotFound {
 		return err
 	}
 
-	rec := NewRecord(id.Bytes())
-	if val != nil {
-		rec.Sequence = val.Sequence + 1
+	if prev != nil {
+		rec.Sequence = prev.Sequence + 1
 	}
 
 	if err := rec.Sign(key); err != nil {
@@ -118,6 +249,27 @@ func (s *System) Publish(ctx context.Context, key crypto.PrivKey, id cid.Cid) er
 	return s.PutValue(ctx, peerID, rec)
 }
 
+// Republish periodically publishes the value returned by fn so that it does not expire.
+func (s *System) Republish(ctx context.Context, key crypto.PrivKey, interval time.Duration, fn func() (cid.Cid, error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		id, err := fn()
+		if err != nil {
+			continue
+		}
+
+		s.Publish(ctx, key, id)
+	}
+}
+
 // Resolve returns the latest value from the topic with the given peer ID.
 func (s *System) Resolve(ctx context.Context, id peer.ID) (cid.Cid, error) {
 	rec, err := s.GetValue(ctx, id)
@@ -0,0 +1,201 @@
+package name
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	cid "github.com/ipfs/go-cid"
+	datastore "github.com/ipfs/go-datastore"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/libp2p/go-libp2p-core/routing"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	namesys "github.com/libp2p/go-libp2p-pubsub-router"
+	mocknet "github.com/libp2p/go-libp2p/p2p/net/mock"
+
+	"github.com/multiverse-vcs/go-multiverse/internal/p2p"
+)
+
+// mockRouter is a value store backed by a map.
+type mockRouter map[string][]byte
+
+func (r mockRouter) PutValue(ctx context.Context, key string, val []byte, opts ...routing.Option) error {
+	r[key] = val
+	return nil
+}
+
+func (r mockRouter) GetValue(ctx context.Context, key string, opts ...routing.Option) ([]byte, error) {
+	val, ok := r[key]
+	if !ok {
+		return nil, routing.ErrNotFound
+	}
+
+	return val, nil
+}
+
+func (r mockRouter) SearchValue(ctx context.Context, key string, opts ...routing.Option) (<-chan []byte, error) {
+	out := make(chan []byte, 1)
+	defer close(out)
+
+	if val, ok := r[key]; ok {
+		out <- val
+	}
+
+	return out, nil
+}
+
+func TestSearchValueFallback(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mn := mocknet.New(ctx)
+
+	host, err := mn.GenPeer()
+	if err != nil {
+		t.Fatal("failed to create host")
+	}
+
+	sub, err := pubsub.NewGossipSub(ctx, host)
+	if err != nil {
+		t.Fatal("failed to create pubsub")
+	}
+
+	values, err := namesys.NewPubsubValueStore(ctx, host, sub, Validator{})
+	if err != nil {
+		t.Fatal("failed to create value store")
+	}
+
+	router := make(mockRouter)
+	sys := &System{
+		values: values,
+		router: router,
+		store:  newStore(datastore.NewMapDatastore()),
+	}
+
+	key, err := p2p.GenerateKey()
+	if err != nil {
+		t.Fatal("failed to generate key")
+	}
+
+	peerID, err := peer.IDFromPrivateKey(key)
+	if err != nil {
+		t.Fatal("failed to get peer id")
+	}
+
+	timeout, cancelTimeout := context.WithTimeout(ctx, time.Second)
+	defer cancelTimeout()
+
+	if _, err := sys.Search(timeout, peerID); err != routing.ErrNotFound {
+		t.Fatal("expected search to fail")
+	}
+
+	id, err := cid.Decode("bafyreiakcek7msekxf67tdvmsjbkyxus4iy6j3ed5n3hgfelly26hlm2lu")
+	if err != nil {
+		t.Fatal("failed to decode cid")
+	}
+
+	rec := NewRecord(id.Bytes())
+	if err := rec.Sign(key); err != nil {
+		t.Fatal("failed to sign record")
+	}
+
+	val, err := rec.Bytes()
+	if err != nil {
+		t.Fatal("failed to encode record")
+	}
+
+	// only the dht has the record
+	router[TopicForPeerID(peerID)] = val
+
+	match, err := sys.Search(ctx, peerID)
+	if err != nil {
+		t.Fatal("failed to search")
+	}
+
+	if match != id {
+		t.Error("search returned unexpected id")
+	}
+}
+
+func TestSearchValueExpired(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mn := mocknet.New(ctx)
+
+	host, err := mn.GenPeer()
+	if err != nil {
+		t.Fatal("failed to create host")
+	}
+
+	sub, err := pubsub.NewGossipSub(ctx, host)
+	if err != nil {
+		t.Fatal("failed to create pubsub")
+	}
+
+	values, err := namesys.NewPubsubValueStore(ctx, host, sub, Validator{})
+	if err != nil {
+		t.Fatal("failed to create value store")
+	}
+
+	dstore := datastore.NewMapDatastore()
+	router := make(mockRouter)
+	sys := &System{
+		values: values,
+		router: router,
+		store:  newStore(dstore),
+	}
+
+	key, err := p2p.GenerateKey()
+	if err != nil {
+		t.Fatal("failed to generate key")
+	}
+
+	peerID, err := peer.IDFromPrivateKey(key)
+	if err != nil {
+		t.Fatal("failed to get peer id")
+	}
+
+	id, err := cid.Decode("bafyreiakcek7msekxf67tdvmsjbkyxus4iy6j3ed5n3hgfelly26hlm2lu")
+	if err != nil {
+		t.Fatal("failed to decode cid")
+	}
+
+	expired := NewRecord(id.Bytes())
+	expired.Sequence = 1
+	expired.EOL = time.Now().Add(-time.Minute).UnixNano()
+
+	if err := expired.Sign(key); err != nil {
+		t.Fatal("failed to sign record")
+	}
+
+	expiredVal, err := expired.Bytes()
+	if err != nil {
+		t.Fatal("failed to encode record")
+	}
+
+	older := NewRecord(id.Bytes())
+	if err := older.Sign(key); err != nil {
+		t.Fatal("failed to sign record")
+	}
+
+	olderVal, err := older.Bytes()
+	if err != nil {
+		t.Fatal("failed to encode record")
+	}
+
+	// the store has a newer record that expired after it was saved
+	topic := TopicForPeerID(peerID)
+	if err := dstore.Put(storePrefix.ChildString(topic), expiredVal); err != nil {
+		t.Fatal("failed to store record")
+	}
+
+	router[topic] = olderVal
+
+	timeout, cancelTimeout := context.WithTimeout(ctx, time.Second)
+	defer cancelTimeout()
+
+	if _, err := sys.SearchValue(timeout, peerID); err != routing.ErrNotFound {
+		t.Error("expected expired record to not be found")
+	}
+}
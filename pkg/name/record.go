@@ -1,10 +1,19 @@
 package name
 
 import (
+	"time"
+
 	cbornode "github.com/ipfs/go-ipld-cbor"
 	"github.com/libp2p/go-libp2p-core/crypto"
 )
 
+const (
+	// DefaultLifetime is the duration a published record is valid for.
+	DefaultLifetime = 24 * time.Hour
+	// RepublishInterval is the time between republishing records.
+	RepublishInterval = 4 * time.Hour
+)
+
 func init() {
 	cbornode.RegisterCborType(Payload{})
 	cbornode.RegisterCborType(Record{})
@@ -16,6 +25,8 @@ type Payload struct {
 	Value []byte
 	// Sequence is a version and nonce.
 	Sequence uint64
+	// EOL is the expiration time in unix nanoseconds.
+	EOL int64
 }
 
 // Record is used for record signatures.
@@ -36,13 +47,21 @@ func RecordFromCBOR(data []byte) (*Record, error) {
 	return &rec, nil
 }
 
-// NewRecord returns a new record.
+// NewRecord returns a new record that expires after the default lifetime.
 func NewRecord(value []byte) *Record {
 	return &Record{
-		Payload: Payload{Value: value},
+		Payload: Payload{
+			Value: value,
+			EOL:   time.Now().Add(DefaultLifetime).UnixNano(),
+		},
 	}
 }
 
+// Expired returns true if the record is no longer valid.
+func (r *Record) Expired() bool {
+	return time.Now().UnixNano() > r.EOL
+}
+
 // Bytes returns the raw bytes of the record.
 func (r *Record) Bytes() ([]byte, error) {
 	return cbornode.DumpObject(r)
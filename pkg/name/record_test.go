@@ -2,6 +2,9 @@ package name
 
 import (
 	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/peer"
 
 	"github.com/multiverse-vcs/go-multiverse/internal/p2p"
 )
@@ -29,3 +32,59 @@ func TestRecordSignature(t *testing.T) {
 		t.Error("envelope signature does not match")
 	}
 }
+
+func TestRecordExpired(t *testing.T) {
+	key, err := p2p.GenerateKey()
+	if err != nil {
+		t.Fatal("failed to generate key")
+	}
+
+	peerID, err := peer.IDFromPrivateKey(key)
+	if err != nil {
+		t.Fatal("failed to get peer id")
+	}
+
+	rec := NewRecord([]byte("bafyreiakcek7msekxf67tdvmsjbkyxus4iy6j3ed5n3hgfelly26hlm2lu"))
+	if rec.Expired() {
+		t.Fatal("expected record to be valid")
+	}
+
+	if err := rec.Sign(key); err != nil {
+		t.Fatal("failed to sign record")
+	}
+
+	data, err := rec.Bytes()
+	if err != nil {
+		t.Fatal("failed to encode record")
+	}
+
+	if err := (Validator{}).Validate(TopicForPeerID(peerID), data); err != nil {
+		t.Fatal("expected record to be valid")
+	}
+
+	rec.EOL = time.Now().Add(-time.Minute).UnixNano()
+	if err := rec.Sign(key); err != nil {
+		t.Fatal("failed to sign record")
+	}
+
+	data, err = rec.Bytes()
+	if err != nil {
+		t.Fatal("failed to encode record")
+	}
+
+	if err := (Validator{}).Validate(TopicForPeerID(peerID), data); err == nil {
+		t.Error("expected expired record to be invalid")
+	}
+
+	// changing the expiration invalidates the signature
+	rec.EOL = time.Now().Add(time.Hour).UnixNano()
+
+	data, err = rec.Bytes()
+	if err != nil {
+		t.Fatal("failed to encode record")
+	}
+
+	if err := (Validator{}).Validate(TopicForPeerID(peerID), data); err == nil {
+		t.Error("expected tampered record to be invalid")
+	}
+}
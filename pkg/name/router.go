@@ -0,0 +1,30 @@
+package name
+
+import (
+	"context"
+
+	"github.com/libp2p/go-libp2p-core/host"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/libp2p/go-libp2p-core/protocol"
+	dht "github.com/libp2p/go-libp2p-kad-dht"
+)
+
+// NewRouter returns a dht that stores name records.
+//
+// The ipfs dht rejects custom validators so name records use a separate
+// protocol that is only served by multiverse peers. The routing table is
+// filled from the bootstrap peers and any connected peer that speaks it.
+func NewRouter(ctx context.Context, host host.Host, bootstrap []peer.AddrInfo) (*dht.IpfsDHT, error) {
+	router, err := dht.New(ctx, host,
+		dht.ProtocolPrefix(protocol.ID("/"+Namespace)),
+		dht.NamespacedValidator(Namespace, Validator{}),
+		dht.Mode(dht.ModeServer),
+		dht.DisableProviders(),
+		dht.BootstrapPeers(bootstrap...),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return router, router.Bootstrap(ctx)
+}
@@ -0,0 +1,88 @@
+package name
+
+import (
+	"time"
+
+	datastore "github.com/ipfs/go-datastore"
+	"github.com/ipfs/go-datastore/namespace"
+	"github.com/ipfs/go-datastore/query"
+)
+
+// StoreInterval is the time between saving received records.
+const StoreInterval = time.Minute
+
+// storePrefix is the datastore namespace for name records.
+var storePrefix = datastore.NewKey("names")
+
+// store persists the best known record for each key.
+type store struct {
+	dstore    datastore.Datastore
+	validator Validator
+}
+
+// newStore returns a store that saves records in the given datastore.
+func newStore(dstore datastore.Datastore) *store {
+	return &store{
+		dstore: namespace.Wrap(dstore, storePrefix),
+	}
+}
+
+// Get returns the stored record value for the given key.
+func (s *store) Get(key string) ([]byte, error) {
+	return s.dstore.Get(datastore.NewKey(key))
+}
+
+// Record returns the decoded stored record for the given key.
+func (s *store) Record(key string) (*Record, error) {
+	val, err := s.Get(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return RecordFromCBOR(val)
+}
+
+// Put saves the record value if it is valid and better than the stored value.
+func (s *store) Put(key string, value []byte) error {
+	if err := s.validator.Validate(key, value); err != nil {
+		return err
+	}
+
+	prev, err := s.Get(key)
+	if err != nil && err != datastore.ErrNotFound {
+		return err
+	}
+
+	if prev != nil {
+		best, err := s.validator.Select(key, [][]byte{prev, value})
+		if err != nil {
+			return err
+		}
+
+		if best == 0 {
+			return nil
+		}
+	}
+
+	return s.dstore.Put(datastore.NewKey(key), value)
+}
+
+// All returns all stored record values keyed by record key.
+func (s *store) All() (map[string][]byte, error) {
+	results, err := s.dstore.Query(query.Query{})
+	if err != nil {
+		return nil, err
+	}
+	defer results.Close()
+
+	values := make(map[string][]byte)
+	for res := range results.Next() {
+		if res.Error != nil {
+			return nil, res.Error
+		}
+
+		values[res.Key] = res.Value
+	}
+
+	return values, nil
+}
@@ -0,0 +1,85 @@
+package name
+
+import (
+	"testing"
+
+	datastore "github.com/ipfs/go-datastore"
+	"github.com/libp2p/go-libp2p-core/peer"
+
+	"github.com/multiverse-vcs/go-multiverse/internal/p2p"
+)
+
+func TestStore(t *testing.T) {
+	key, err := p2p.GenerateKey()
+	if err != nil {
+		t.Fatal("failed to generate key")
+	}
+
+	peerID, err := peer.IDFromPrivateKey(key)
+	if err != nil {
+		t.Fatal("failed to get peer id")
+	}
+
+	topic := TopicForPeerID(peerID)
+	values := make([][]byte, 2)
+
+	for i := range values {
+		rec := NewRecord([]byte("bafyreiakcek7msekxf67tdvmsjbkyxus4iy6j3ed5n3hgfelly26hlm2lu"))
+		rec.Sequence = uint64(i)
+
+		if err := rec.Sign(key); err != nil {
+			t.Fatal("failed to sign record")
+		}
+
+		if values[i], err = rec.Bytes(); err != nil {
+			t.Fatal("failed to encode record")
+		}
+	}
+
+	dstore := datastore.NewMapDatastore()
+	store := newStore(dstore)
+
+	if err := store.Put(topic, values[1]); err != nil {
+		t.Fatal("failed to put record")
+	}
+
+	if err := store.Put(topic, values[0]); err != nil {
+		t.Fatal("failed to put record")
+	}
+
+	// reopen to ensure records are persisted
+	store = newStore(dstore)
+
+	val, err := store.Get(topic)
+	if err != nil {
+		t.Fatal("failed to get record")
+	}
+
+	rec, err := RecordFromCBOR(val)
+	if err != nil {
+		t.Fatal("failed to decode record")
+	}
+
+	if rec.Sequence != 1 {
+		t.Error("expected latest record")
+	}
+
+	all, err := store.All()
+	if err != nil {
+		t.Fatal("failed to query records")
+	}
+
+	if len(all) != 1 || all[topic] == nil {
+		t.Error("unexpected stored records")
+	}
+
+	other := NewRecord([]byte("forged"))
+	data, err := other.Bytes()
+	if err != nil {
+		t.Fatal("failed to encode record")
+	}
+
+	if err := store.Put(topic, data); err == nil {
+		t.Error("expected invalid record error")
+	}
+}
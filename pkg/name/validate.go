@@ -47,6 +47,10 @@ func (v Validator) Validate(key string, value []byte) error {
 		return errors.New("signature does not match")
 	}
 
+	if rec.Expired() {
+		return errors.New("record expired")
+	}
+
 	return nil
 }
 
@@ -11,6 +11,13 @@ import (
 	"github.com/multiformats/go-multihash"
 )
 
+const (
+	// NameKey is the metadata key of the author display name.
+	NameKey = "name"
+	// EmailKey is the metadata key of the author email address.
+	EmailKey = "email"
+)
+
 // Author contains info about a user.
 type Author struct {
 	// Repositories is a map of repositories.
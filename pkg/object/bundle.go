@@ -0,0 +1,73 @@
+package object
+
+import (
+	"context"
+	"encoding/json"
+
+	cid "github.com/ipfs/go-cid"
+	cbornode "github.com/ipfs/go-ipld-cbor"
+	ipld "github.com/ipfs/go-ipld-format"
+	"github.com/multiformats/go-multihash"
+)
+
+// Bundle is the manifest of a car used to transfer repository history offline.
+type Bundle struct {
+	// Branches is a map of names to commit CIDs.
+	Branches map[string]cid.Cid `json:"branches"`
+	// Tags is a map of names to tag CIDs.
+	Tags map[string]cid.Cid `json:"tags"`
+	// Prerequisites contains commit CIDs that are required but not included.
+	Prerequisites []cid.Cid `json:"prerequisites"`
+}
+
+// GetBundle returns the bundle with the given CID.
+func GetBundle(ctx context.Context, ds ipld.NodeGetter, id cid.Cid) (*Bundle, error) {
+	node, err := ds.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return BundleFromCBOR(node.RawData())
+}
+
+// AddBundle adds a bundle to the given dag.
+func AddBundle(ctx context.Context, ds ipld.NodeAdder, bundle *Bundle) (cid.Cid, error) {
+	node, err := cbornode.WrapObject(bundle, multihash.SHA2_256, -1)
+	if err != nil {
+		return cid.Cid{}, err
+	}
+
+	if err := ds.Add(ctx, node); err != nil {
+		return cid.Cid{}, err
+	}
+
+	return node.Cid(), nil
+}
+
+// BundleFromJSON decodes a bundle from json.
+func BundleFromJSON(data []byte) (*Bundle, error) {
+	var bundle Bundle
+	if err := json.Unmarshal(data, &bundle); err != nil {
+		return nil, err
+	}
+
+	return &bundle, nil
+}
+
+// BundleFromCBOR decodes a bundle from an ipld node.
+func BundleFromCBOR(data []byte) (*Bundle, error) {
+	var bundle Bundle
+	if err := cbornode.DecodeInto(data, &bundle); err != nil {
+		return nil, err
+	}
+
+	return &bundle, nil
+}
+
+// NewBundle returns a new bundle.
+func NewBundle() *Bundle {
+	return &Bundle{
+		Branches: make(map[string]cid.Cid),
+		Tags:     make(map[string]cid.Cid),
+	}
+}
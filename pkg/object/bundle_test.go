@@ -0,0 +1,60 @@
+package object
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/ipfs/go-merkledag/dagutils"
+)
+
+func TestBundleRoundtrip(t *testing.T) {
+	ctx := context.Background()
+	dag := dagutils.NewMemoryDagService()
+
+	data, err := os.ReadFile("testdata/bundle.json")
+	if err != nil {
+		t.Fatal("failed to read file")
+	}
+
+	bundle, err := BundleFromJSON(data)
+	if err != nil {
+		t.Fatal("failed to decode bundle json")
+	}
+
+	id, err := AddBundle(ctx, dag, bundle)
+	if err != nil {
+		t.Fatal("failed to add bundle to dag")
+	}
+
+	bundle, err = GetBundle(ctx, dag, id)
+	if err != nil {
+		t.Fatal("failed to get bundle from dag")
+	}
+
+	if len(bundle.Branches) != 1 {
+		t.Fatal("unexpected branches")
+	}
+
+	branch, ok := bundle.Branches["default"]
+	if !ok || branch.String() != "bafyreieo2mhnqyqntenwyndzxoovw5nhbpit727kjrl3mjbyb5nv6zs2pu" {
+		t.Error("unexpected branch value")
+	}
+
+	if len(bundle.Tags) != 1 {
+		t.Fatal("unexpected tags")
+	}
+
+	tag, ok := bundle.Tags["v0.0.1"]
+	if !ok || tag.String() != "bafyreieo2mhnqyqntenwyndzxoovw5nhbpit727kjrl3mjbyb5nv6zs3pu" {
+		t.Error("unexpected tag value")
+	}
+
+	if len(bundle.Prerequisites) != 1 {
+		t.Fatal("unexpected prerequisites")
+	}
+
+	if bundle.Prerequisites[0].String() != "bafyreieo2mhnqyqntenwyndzxoovw5nhbpit727kjrl3mjbyb5nv6zs4pu" {
+		t.Error("unexpected prerequisite value")
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/ipfs/go-cid"
 	cbornode "github.com/ipfs/go-ipld-cbor"
 	ipld "github.com/ipfs/go-ipld-format"
+	"github.com/libp2p/go-libp2p-core/crypto"
 	"github.com/multiformats/go-multihash"
 )
 
@@ -21,6 +22,12 @@ type Commit struct {
 	Parents []cid.Cid `json:"parents"`
 	// Tree is the root CID of the repo file tree.
 	Tree cid.Cid `json:"tree"`
+	// Author is the identity of the person who made the changes.
+	Author *Identity `json:"author"`
+	// Committer is the identity of the person who created the commit.
+	Committer *Identity `json:"committer"`
+	// Signature is an optional signature of the commit made by the author.
+	Signature []byte `json:"signature"`
 	// Metadata contains additional data.
 	Metadata map[string]string `json:"metadata"`
 }
@@ -96,3 +103,33 @@ func (c *Commit) ParentLinks() []*ipld.Link {
 
 	return out
 }
+
+// Sign creates a signature of the commit made by the author.
+//
+// The author is created from the key if it is not set.
+func (c *Commit) Sign(key crypto.PrivKey) error {
+	if c.Author == nil {
+		c.Author = &Identity{}
+	}
+
+	signature, err := c.Author.sign(key, c.payload)
+	if err != nil {
+		return err
+	}
+
+	c.Signature = signature
+	return nil
+}
+
+// Verify checks if the signature of the commit was made by the author.
+func (c *Commit) Verify() (bool, error) {
+	return c.Author.verify(c.Signature, c.payload)
+}
+
+// payload returns the signed bytes of the commit.
+func (c *Commit) payload() ([]byte, error) {
+	unsigned := *c
+	unsigned.Signature = nil
+
+	return cbornode.DumpObject(&unsigned)
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/ipfs/go-merkledag/dagutils"
+	"github.com/libp2p/go-libp2p-core/crypto"
 )
 
 func TestCommitRoundtrip(t *testing.T) {
@@ -79,3 +80,66 @@ func TestCommitParentLinks(t *testing.T) {
 		t.Error("parent link cid does not match")
 	}
 }
+
+func TestCommitSignature(t *testing.T) {
+	ctx := context.Background()
+	dag := dagutils.NewMemoryDagService()
+
+	key, _, err := crypto.GenerateKeyPair(crypto.Ed25519, -1)
+	if err != nil {
+		t.Fatal("failed to generate key")
+	}
+
+	data, err := os.ReadFile("testdata/commit.json")
+	if err != nil {
+		t.Fatal("failed to read file")
+	}
+
+	commit, err := CommitFromJSON(data)
+	if err != nil {
+		t.Fatal("failed to decode commit json")
+	}
+
+	commit.Author = &Identity{Name: "Author", Email: "author@example.com"}
+	if err := commit.Sign(key); err != nil {
+		t.Fatal("failed to sign commit")
+	}
+
+	id, err := AddCommit(ctx, dag, commit)
+	if err != nil {
+		t.Fatal("failed to add commit to dag")
+	}
+
+	commit, err = GetCommit(ctx, dag, id)
+	if err != nil {
+		t.Fatal("failed to get commit from dag")
+	}
+
+	if commit.Author.Name != "Author" || commit.Author.Email != "author@example.com" {
+		t.Error("author does not match")
+	}
+
+	if commit.Committer != nil {
+		t.Error("committer does not match")
+	}
+
+	match, err := commit.Verify()
+	if err != nil {
+		t.Fatal("failed to verify commit")
+	}
+
+	if !match {
+		t.Error("expected signature to match")
+	}
+
+	commit.Tree = commit.Parents[0]
+
+	match, err = commit.Verify()
+	if err != nil {
+		t.Fatal("failed to verify commit")
+	}
+
+	if match {
+		t.Error("expected signature to not match")
+	}
+}
@@ -0,0 +1,76 @@
+package object
+
+import (
+	"fmt"
+
+	"github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/libp2p/go-libp2p-core/peer"
+)
+
+// Identity contains info about the creator of an object.
+type Identity struct {
+	// PeerID is the peer ID of the creator.
+	PeerID peer.ID `json:"peer_id"`
+	// Name is the display name of the creator.
+	Name string `json:"name"`
+	// Email is the email address of the creator.
+	Email string `json:"email"`
+}
+
+// String returns a human readable version of the identity.
+func (i *Identity) String() string {
+	out := i.Name
+	if i.Email != "" {
+		out = fmt.Sprintf("%s <%s>", out, i.Email)
+	}
+
+	if i.PeerID != "" {
+		out = fmt.Sprintf("%s (%s)", out, i.PeerID.Pretty())
+	}
+
+	return out
+}
+
+// sign returns a signature of the payload made by the identity key.
+//
+// The peer ID of the identity is set if it is empty.
+func (i *Identity) sign(key crypto.PrivKey, payload func() ([]byte, error)) ([]byte, error) {
+	id, err := peer.IDFromPrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if i.PeerID == "" {
+		i.PeerID = id
+	}
+
+	if i.PeerID != id {
+		return nil, fmt.Errorf("key does not match peer ID %s", i.PeerID.Pretty())
+	}
+
+	data, err := payload()
+	if err != nil {
+		return nil, err
+	}
+
+	return key.Sign(data)
+}
+
+// verify checks if the signature of the payload was made by the identity key.
+func (i *Identity) verify(signature []byte, payload func() ([]byte, error)) (bool, error) {
+	if i == nil || len(signature) == 0 {
+		return false, nil
+	}
+
+	key, err := i.PeerID.ExtractPublicKey()
+	if err != nil {
+		return false, err
+	}
+
+	data, err := payload()
+	if err != nil {
+		return false, err
+	}
+
+	return key.Verify(data, signature)
+}
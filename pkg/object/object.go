@@ -24,6 +24,9 @@ var timeAtlasEntry = atlas.BuildEntry(time.Time{}).
 func init() {
 	cbornode.RegisterCborType(timeAtlasEntry)
 	cbornode.RegisterCborType(Author{})
+	cbornode.RegisterCborType(Bundle{})
 	cbornode.RegisterCborType(Commit{})
+	cbornode.RegisterCborType(Identity{})
 	cbornode.RegisterCborType(Repository{})
+	cbornode.RegisterCborType(Tag{})
 }
@@ -0,0 +1,112 @@
+package object
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	cid "github.com/ipfs/go-cid"
+	cbornode "github.com/ipfs/go-ipld-cbor"
+	ipld "github.com/ipfs/go-ipld-format"
+	"github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/multiformats/go-multihash"
+)
+
+// Tag contains info about a named commit.
+type Tag struct {
+	// Target is the CID of the tagged commit.
+	Target cid.Cid `json:"target"`
+	// Tagger is the identity of the tag creator.
+	Tagger *Identity `json:"tagger"`
+	// Date is the timestamp of when the tag was created.
+	Date time.Time `json:"date"`
+	// Message is a description of the tag.
+	Message string `json:"message"`
+	// Signature is an optional signature of the tag made by the tagger.
+	Signature []byte `json:"signature"`
+	// Metadata contains additional data.
+	Metadata map[string]string `json:"metadata"`
+}
+
+// GetTag returns the tag with the given CID.
+func GetTag(ctx context.Context, ds ipld.NodeGetter, id cid.Cid) (*Tag, error) {
+	node, err := ds.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return TagFromCBOR(node.RawData())
+}
+
+// AddTag adds a tag to the given dag.
+func AddTag(ctx context.Context, ds ipld.NodeAdder, tag *Tag) (cid.Cid, error) {
+	node, err := cbornode.WrapObject(tag, multihash.SHA2_256, -1)
+	if err != nil {
+		return cid.Cid{}, err
+	}
+
+	if err := ds.Add(ctx, node); err != nil {
+		return cid.Cid{}, err
+	}
+
+	return node.Cid(), nil
+}
+
+// TagFromJSON decodes a tag from json.
+func TagFromJSON(data []byte) (*Tag, error) {
+	var tag Tag
+	if err := json.Unmarshal(data, &tag); err != nil {
+		return nil, err
+	}
+
+	return &tag, nil
+}
+
+// TagFromCBOR decodes a tag from an ipld node.
+func TagFromCBOR(data []byte) (*Tag, error) {
+	var tag Tag
+	if err := cbornode.DecodeInto(data, &tag); err != nil {
+		return nil, err
+	}
+
+	return &tag, nil
+}
+
+// NewTag returns a new tag with default values.
+func NewTag(target cid.Cid) *Tag {
+	return &Tag{
+		Target:   target,
+		Date:     time.Now(),
+		Metadata: make(map[string]string),
+	}
+}
+
+// Sign creates a signature of the tag made by the tagger.
+//
+// The tagger is created from the key if it is not set.
+func (t *Tag) Sign(key crypto.PrivKey) error {
+	if t.Tagger == nil {
+		t.Tagger = &Identity{}
+	}
+
+	signature, err := t.Tagger.sign(key, t.payload)
+	if err != nil {
+		return err
+	}
+
+	t.Signature = signature
+	return nil
+}
+
+// Verify checks if the signature of the tag was made by the tagger.
+func (t *Tag) Verify() (bool, error) {
+	return t.Tagger.verify(t.Signature, t.payload)
+}
+
+// payload returns the signed bytes of the tag.
+func (t *Tag) payload() ([]byte, error) {
+	unsigned := *t
+	unsigned.Signature = nil
+
+	return cbornode.DumpObject(&unsigned)
+}
@@ -0,0 +1,120 @@
+package object
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/ipfs/go-merkledag/dagutils"
+	"github.com/libp2p/go-libp2p-core/crypto"
+)
+
+func TestTagRoundtrip(t *testing.T) {
+	ctx := context.Background()
+	dag := dagutils.NewMemoryDagService()
+
+	data, err := os.ReadFile("testdata/tag.json")
+	if err != nil {
+		t.Fatal("failed to read file")
+	}
+
+	tag, err := TagFromJSON(data)
+	if err != nil {
+		t.Fatal("failed to decode tag json")
+	}
+
+	id, err := AddTag(ctx, dag, tag)
+	if err != nil {
+		t.Fatal("failed to add tag to dag")
+	}
+
+	tag, err = GetTag(ctx, dag, id)
+	if err != nil {
+		t.Fatal("failed to get tag from dag")
+	}
+
+	if tag.Message != "release v1.0.0" {
+		t.Error("message does not match")
+	}
+
+	if tag.Date.Format(time.RFC3339) != "2020-10-25T15:26:12-07:00" {
+		t.Error("date does not match")
+	}
+
+	if tag.Target.String() != "bagaybqabciqeutn2u7n3zuk5b4ykgfwpkekb7ctgnlwik5zfr6bcukvknj2jtpa" {
+		t.Error("target does not match")
+	}
+
+	if tag.Tagger.PeerID.Pretty() != "12D3KooWApcMRD2pc2GbuZEwKbtvbxqNTPkrGZrrZ4Mzb7MSSyiP" {
+		t.Error("tagger does not match")
+	}
+
+	if tag.Tagger.Name != "Tagger" || tag.Tagger.Email != "tagger@example.com" {
+		t.Error("tagger does not match")
+	}
+
+	meta, ok := tag.Metadata["foo"]
+	if !ok || meta != "bar" {
+		t.Error("metadata does not match")
+	}
+}
+
+func TestTagSignature(t *testing.T) {
+	ctx := context.Background()
+	dag := dagutils.NewMemoryDagService()
+
+	key, _, err := crypto.GenerateKeyPair(crypto.Ed25519, -1)
+	if err != nil {
+		t.Fatal("failed to generate key")
+	}
+
+	data, err := os.ReadFile("testdata/tag.json")
+	if err != nil {
+		t.Fatal("failed to read file")
+	}
+
+	tag, err := TagFromJSON(data)
+	if err != nil {
+		t.Fatal("failed to decode tag json")
+	}
+
+	if err := tag.Sign(key); err == nil {
+		t.Fatal("expected tagger mismatch error")
+	}
+
+	tag.Tagger = nil
+	if err := tag.Sign(key); err != nil {
+		t.Fatal("failed to sign tag")
+	}
+
+	id, err := AddTag(ctx, dag, tag)
+	if err != nil {
+		t.Fatal("failed to add tag to dag")
+	}
+
+	tag, err = GetTag(ctx, dag, id)
+	if err != nil {
+		t.Fatal("failed to get tag from dag")
+	}
+
+	match, err := tag.Verify()
+	if err != nil {
+		t.Fatal("failed to verify tag")
+	}
+
+	if !match {
+		t.Error("expected signature to match")
+	}
+
+	tag.Message = "forged"
+
+	match, err = tag.Verify()
+	if err != nil {
+		t.Fatal("failed to verify tag")
+	}
+
+	if match {
+		t.Error("expected signature to not match")
+	}
+}
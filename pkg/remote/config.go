@@ -5,6 +5,8 @@ import (
 	"os"
 	"path/filepath"
 
+	cid "github.com/ipfs/go-cid"
+	"github.com/libp2p/go-libp2p-core/peer"
 	"github.com/multiverse-vcs/go-multiverse/pkg/object"
 )
 
@@ -13,12 +15,18 @@ const ConfigFile = "config.json"
 
 // Config contains repository info.
 type Config struct {
+	// Aliases maps local names to author peer IDs.
+	Aliases map[string]peer.ID `json:"aliases"`
 	// Author contains published repositories.
 	Author *object.Author `json:"author"`
+	// Bootstrap contains p2p addresses of peers that serve name records.
+	Bootstrap []string `json:"bootstrap"`
 	// HttpAddress is the http listener address.
 	HttpAddress string `json:"http_address"`
 	// ListenAddresses contains libp2p listener addresses.
 	ListenAddresses []string `json:"listen_addresses"`
+	// Pins maps remote paths to pinned repository CIDs.
+	Pins map[string]cid.Cid `json:"pins"`
 	// PrivateKey is the private key of the remote.
 	PrivateKey string `json:"private_key"`
 
@@ -28,9 +36,11 @@ type Config struct {
 // New returns a config with default settings.
 func NewConfig(root string) *Config {
 	return &Config{
+		Aliases:         make(map[string]peer.ID),
 		Author:          object.NewAuthor(),
 		HttpAddress:     "localhost:8421",
 		ListenAddresses: []string{"/ip4/0.0.0.0/tcp/8420"},
+		Pins:            make(map[string]cid.Cid),
 		path:            filepath.Join(root, ConfigFile),
 	}
 }
@@ -0,0 +1,93 @@
+package remote
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+)
+
+const (
+	// DNSPrefix is the subdomain containing author TXT records.
+	DNSPrefix = "_multiverse."
+	// DNSKey is the prefix of TXT record values containing a peer ID.
+	DNSKey = "multiverse="
+)
+
+// lookupTXT returns the DNS TXT records for the given domain.
+var lookupTXT = net.DefaultResolver.LookupTXT
+
+// ParsePath splits the remote path into author and repository names.
+func ParsePath(remote string) (string, string, error) {
+	parts := strings.Split(remote, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", errors.New("invalid remote path")
+	}
+
+	return parts[0], parts[1], nil
+}
+
+// ResolvePath returns the peer ID and repository name of the remote path.
+func (c *Config) ResolvePath(ctx context.Context, remote string) (peer.ID, string, error) {
+	pname, rname, err := ParsePath(remote)
+	if err != nil {
+		return "", "", err
+	}
+
+	peerID, err := c.ResolveName(ctx, pname)
+	if err != nil {
+		return "", "", err
+	}
+
+	return peerID, rname, nil
+}
+
+// ResolveName returns the peer ID for the given alias, peer ID, or domain name.
+func (c *Config) ResolveName(ctx context.Context, name string) (peer.ID, error) {
+	if peerID, ok := c.Aliases[name]; ok {
+		return peerID, nil
+	}
+
+	if peerID, err := peer.Decode(name); err == nil {
+		return peerID, nil
+	}
+
+	if !strings.Contains(name, ".") {
+		return "", errors.New("invalid author name")
+	}
+
+	return ResolveDNS(ctx, name)
+}
+
+// ResolveDNS returns the peer ID from the TXT record of the given domain.
+func ResolveDNS(ctx context.Context, domain string) (peer.ID, error) {
+	records, err := lookupTXT(ctx, DNSPrefix+domain)
+	if err != nil {
+		return "", err
+	}
+
+	for _, txt := range records {
+		if !strings.HasPrefix(txt, DNSKey) {
+			continue
+		}
+
+		return peer.Decode(strings.TrimPrefix(txt, DNSKey))
+	}
+
+	return "", errors.New("no multiverse record found")
+}
+
+// ValidateAlias returns an error if the name cannot be used as an alias.
+func ValidateAlias(name string) error {
+	if name == "" || strings.Contains(name, "/") {
+		return errors.New("invalid alias name")
+	}
+
+	if _, err := peer.Decode(name); err == nil {
+		return errors.New("alias cannot be a peer id")
+	}
+
+	return nil
+}
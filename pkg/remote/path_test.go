@@ -0,0 +1,65 @@
+package remote
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+)
+
+const testPeerID = "12D3KooWApcMRD2pc2GbuZEwKbtvbxqNTPkrGZrrZ4Mzb7MSSyiP"
+
+func TestParsePath(t *testing.T) {
+	pname, rname, err := ParsePath("example.com/project")
+	if err != nil {
+		t.Fatal("failed to parse path")
+	}
+
+	if pname != "example.com" || rname != "project" {
+		t.Error("unexpected path components")
+	}
+
+	for _, remote := range []string{"", "project", "/project", "example.com/", "a/b/c"} {
+		if _, _, err := ParsePath(remote); err == nil {
+			t.Errorf("expected %q to be invalid", remote)
+		}
+	}
+}
+
+func TestResolveName(t *testing.T) {
+	ctx := context.Background()
+
+	peerID, err := peer.Decode(testPeerID)
+	if err != nil {
+		t.Fatal("failed to decode peer id")
+	}
+
+	defer func() { lookupTXT = net.DefaultResolver.LookupTXT }()
+
+	lookupTXT = func(ctx context.Context, name string) ([]string, error) {
+		if name != "_multiverse.example.com" {
+			t.Fatalf("unexpected dns lookup %s", name)
+		}
+
+		return []string{"v=spf1 -all", DNSKey + testPeerID}, nil
+	}
+
+	config := NewConfig("")
+	config.Aliases["alice"] = peerID
+
+	for _, name := range []string{"alice", testPeerID, "example.com"} {
+		match, err := config.ResolveName(ctx, name)
+		if err != nil {
+			t.Fatalf("failed to resolve %s", name)
+		}
+
+		if match != peerID {
+			t.Errorf("unexpected peer id for %s", name)
+		}
+	}
+
+	if _, err := config.ResolveName(ctx, "bob"); err == nil {
+		t.Error("expected unknown alias to fail")
+	}
+}
@@ -0,0 +1,165 @@
+package remote
+
+import (
+	"context"
+	"errors"
+	"log"
+	"path"
+	"time"
+
+	cid "github.com/ipfs/go-cid"
+	ipld "github.com/ipfs/go-ipld-format"
+	merkledag "github.com/ipfs/go-merkledag"
+	"github.com/libp2p/go-libp2p-core/peer"
+
+	"github.com/multiverse-vcs/go-multiverse/pkg/dag"
+	"github.com/multiverse-vcs/go-multiverse/pkg/object"
+)
+
+const (
+	// GCInterval is the time between garbage collections.
+	GCInterval = time.Hour
+	// PinTimeout is the maximum time to update a pinned repository.
+	PinTimeout = 10 * time.Minute
+)
+
+// Roots returns the CIDs of the author, its repositories, and all pinned repositories.
+func (c *Config) Roots(ctx context.Context, ds ipld.DAGService) ([]cid.Cid, error) {
+	authorID, err := object.AddAuthor(ctx, ds, c.Author)
+	if err != nil {
+		return nil, err
+	}
+
+	roots := []cid.Cid{authorID}
+	for _, id := range c.Author.Repositories {
+		roots = append(roots, id)
+	}
+
+	for _, id := range c.Pins {
+		roots = append(roots, id)
+	}
+
+	return roots, nil
+}
+
+// PinRepository fetches the repository at the remote path and adds it to the pin set.
+//
+// The returned path contains the resolved peer ID of the author.
+func (s *Server) PinRepository(ctx context.Context, remote string) (string, error) {
+	peerID, rname, err := s.Config.ResolvePath(ctx, remote)
+	if err != nil {
+		return "", err
+	}
+
+	defer s.Peer.Blocks.PinLock().Unlock()
+
+	pin := path.Join(peer.Encode(peerID), rname)
+	if err := s.fetchPin(ctx, pin, peerID, rname); err != nil {
+		return "", err
+	}
+
+	return pin, s.Config.Write()
+}
+
+// UnpinRepository removes the repository at the remote path from the pin set.
+func (s *Server) UnpinRepository(ctx context.Context, remote string) (string, error) {
+	peerID, rname, err := s.Config.ResolvePath(ctx, remote)
+	if err != nil {
+		return "", err
+	}
+
+	defer s.Peer.Blocks.PinLock().Unlock()
+
+	pin := path.Join(peer.Encode(peerID), rname)
+	if _, ok := s.Config.Pins[pin]; !ok {
+		return "", errors.New("repository is not pinned")
+	}
+
+	delete(s.Config.Pins, pin)
+	return pin, s.Config.Write()
+}
+
+// fetchPin resolves the latest version of the repository and fetches all of its objects.
+func (s *Server) fetchPin(ctx context.Context, pin string, peerID peer.ID, rname string) error {
+	authorID, err := s.Namesys.Search(ctx, peerID)
+	if err != nil {
+		return err
+	}
+
+	author, err := object.GetAuthor(ctx, s.Peer.DAG, authorID)
+	if err != nil {
+		return err
+	}
+
+	repoID, ok := author.Repositories[rname]
+	if !ok {
+		return errors.New("repository does not exist")
+	}
+
+	if err := merkledag.FetchGraph(ctx, repoID, s.Peer.DAG); err != nil {
+		return err
+	}
+
+	if s.Config.Pins == nil {
+		s.Config.Pins = make(map[string]cid.Cid)
+	}
+
+	s.Config.Pins[pin] = repoID
+	return nil
+}
+
+// updatePins fetches the latest versions of all pinned repositories.
+//
+// Pins that cannot be resolved keep their previous version.
+func (s *Server) updatePins(ctx context.Context) error {
+	defer s.Peer.Blocks.PinLock().Unlock()
+
+	for pin := range s.Config.Pins {
+		peerID, rname, err := s.Config.ResolvePath(ctx, pin)
+		if err != nil {
+			continue
+		}
+
+		fetchCtx, cancel := context.WithTimeout(ctx, PinTimeout)
+		s.fetchPin(fetchCtx, pin, peerID, rname)
+		cancel()
+	}
+
+	return s.Config.Write()
+}
+
+// GC removes all objects that are not reachable from the pinned roots.
+//
+// It returns the number of blocks and bytes removed.
+func (s *Server) GC(ctx context.Context) (int, int64, error) {
+	defer s.Peer.Blocks.GCLock().Unlock()
+
+	roots, err := s.Config.Roots(ctx, s.Peer.Local)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return dag.GC(ctx, s.Peer.Blocks, s.Peer.Local, roots, false)
+}
+
+// collect periodically updates pins and removes unpinned objects.
+func (s *Server) collect(ctx context.Context) {
+	ticker := time.NewTicker(GCInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if err := s.updatePins(ctx); err != nil {
+			log.Printf("failed to update pins: %v", err)
+		}
+
+		if _, _, err := s.GC(ctx); err != nil {
+			log.Printf("failed to collect garbage: %v", err)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package remote
+
+import (
+	"context"
+	"testing"
+
+	cid "github.com/ipfs/go-cid"
+	"github.com/ipfs/go-merkledag/dagutils"
+)
+
+func TestRoots(t *testing.T) {
+	ctx := context.Background()
+	dag := dagutils.NewMemoryDagService()
+
+	repoID, err := cid.Decode("bafyreieo2mhnqyqntenwyndzxoovw5nhbpit727kjrl3mjbyb5nv6zs2pu")
+	if err != nil {
+		t.Fatal("failed to decode cid")
+	}
+
+	pinID, err := cid.Decode("bafyreieo2mhnqyqntenwyndzxoovw5nhbpit727kjrl3mjbyb5nv6zs3pu")
+	if err != nil {
+		t.Fatal("failed to decode cid")
+	}
+
+	config := NewConfig("")
+	config.Author.Repositories["project"] = repoID
+	config.Pins[testPeerID+"/project"] = pinID
+
+	roots, err := config.Roots(ctx, dag)
+	if err != nil {
+		t.Fatal("failed to get roots")
+	}
+
+	if len(roots) != 3 {
+		t.Fatal("unexpected roots")
+	}
+
+	if _, err := dag.Get(ctx, roots[0]); err != nil {
+		t.Error("expected author to be added to dag")
+	}
+
+	if roots[1] != repoID || roots[2] != pinID {
+		t.Error("unexpected root values")
+	}
+}
@@ -5,8 +5,11 @@ import (
 	"os"
 	"path/filepath"
 
+	cid "github.com/ipfs/go-cid"
 	badger "github.com/ipfs/go-ds-badger2"
 	"github.com/ipfs/go-path/resolver"
+	"github.com/libp2p/go-libp2p-core/peer"
+	ma "github.com/multiformats/go-multiaddr"
 
 	"github.com/multiverse-vcs/go-multiverse/internal/p2p"
 	"github.com/multiverse-vcs/go-multiverse/pkg/name"
@@ -60,12 +63,22 @@ func NewServer(ctx context.Context, home string) (*Server, error) {
 		return nil, err
 	}
 
-	peer, err := p2p.NewPeer(ctx, host, router, dstore)
+	peer, err := p2p.NewPeer(ctx, host, router, dstore, config.Roots)
 	if err != nil {
 		return nil, err
 	}
 
-	namesys, err := name.NewSystem(ctx, host, router, dstore)
+	bootstrap, err := parseAddrInfos(config.Bootstrap)
+	if err != nil {
+		return nil, err
+	}
+
+	names, err := name.NewRouter(ctx, host, bootstrap)
+	if err != nil {
+		return nil, err
+	}
+
+	namesys, err := name.NewSystem(ctx, host, router, names, dstore)
 	if err != nil {
 		return nil, err
 	}
@@ -85,13 +98,25 @@ func NewServer(ctx context.Context, home string) (*Server, error) {
 		}
 	}
 
-	return &Server{
+	server := &Server{
 		Config:   config,
 		Peer:     peer,
 		Namesys:  namesys,
 		Resolver: resolver.NewBasicResolver(peer.DAG),
 		Root:     root,
-	}, nil
+	}
+
+	// republish the author before the record expires
+	author := func() (cid.Cid, error) {
+		// handlers change the author while holding a pin lock
+		// so the exclusive lock is needed to read it safely
+		defer peer.Blocks.GCLock().Unlock()
+		return object.AddAuthor(ctx, peer.DAG, server.Config.Author)
+	}
+
+	go namesys.Republish(ctx, key, name.RepublishInterval, author)
+	go server.collect(ctx)
+	return server, nil
 }
 
 // initServer initializes the remote server.
@@ -119,3 +144,18 @@ func initServer(root string) error {
 	config.PrivateKey = enc
 	return config.Write()
 }
+
+// parseAddrInfos returns the peer infos from the list of p2p multiaddrs.
+func parseAddrInfos(addrs []string) ([]peer.AddrInfo, error) {
+	var maddrs []ma.Multiaddr
+	for _, addr := range addrs {
+		maddr, err := ma.NewMultiaddr(addr)
+		if err != nil {
+			return nil, err
+		}
+
+		maddrs = append(maddrs, maddr)
+	}
+
+	return peer.AddrInfosFromP2pAddrs(maddrs...)
+}
@@ -0,0 +1,63 @@
+package author
+
+import (
+	"errors"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+
+	"github.com/multiverse-vcs/go-multiverse/pkg/remote"
+)
+
+// AliasArgs contains the args.
+type AliasArgs struct {
+	// Name is the alias name.
+	Name string `json:"name"`
+	// PeerID is the peer ID of the author.
+	PeerID peer.ID `json:"peerID"`
+	// Delete removes the alias.
+	Delete bool `json:"delete"`
+}
+
+// AliasReply contains the reply
+type AliasReply struct {
+	// Aliases maps alias names to peer IDs.
+	Aliases map[string]peer.ID `json:"aliases"`
+}
+
+// Alias creates or removes a local name for an author.
+//
+// The current aliases are returned when no name is given.
+func (s *Service) Alias(args *AliasArgs, reply *AliasReply) error {
+	defer s.Peer.Blocks.PinLock().Unlock()
+
+	if s.Config.Aliases == nil {
+		s.Config.Aliases = make(map[string]peer.ID)
+	}
+
+	if args.Name == "" {
+		reply.Aliases = s.Config.Aliases
+		return nil
+	}
+
+	if args.Delete {
+		if _, ok := s.Config.Aliases[args.Name]; !ok {
+			return errors.New("alias does not exist")
+		}
+
+		delete(s.Config.Aliases, args.Name)
+		reply.Aliases = s.Config.Aliases
+		return s.Config.Write()
+	}
+
+	if err := remote.ValidateAlias(args.Name); err != nil {
+		return err
+	}
+
+	if err := args.PeerID.Validate(); err != nil {
+		return err
+	}
+
+	s.Config.Aliases[args.Name] = args.PeerID
+	reply.Aliases = s.Config.Aliases
+	return s.Config.Write()
+}
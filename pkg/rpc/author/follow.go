@@ -15,6 +15,8 @@ type FollowReply struct{}
 
 // Follow returns the author for the given peer ID.
 func (s *Service) Follow(args *FollowArgs, reply *FollowReply) error {
+	defer s.Peer.Blocks.PinLock().Unlock()
+
 	if err := args.PeerID.Validate(); err != nil {
 		return err
 	}
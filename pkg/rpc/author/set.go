@@ -0,0 +1,55 @@
+package author
+
+import (
+	"context"
+	"errors"
+
+	"github.com/multiverse-vcs/go-multiverse/internal/p2p"
+	"github.com/multiverse-vcs/go-multiverse/pkg/object"
+)
+
+// SetArgs contains the args.
+type SetArgs struct {
+	// Key is the metadata key.
+	Key string `json:"key"`
+	// Value is the metadata value.
+	Value string `json:"value"`
+}
+
+// SetReply contains the reply
+type SetReply struct{}
+
+// Set updates the server peer's author metadata and publishes the result.
+func (s *Service) Set(args *SetArgs, reply *SetReply) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	defer s.Peer.Blocks.PinLock().Unlock()
+
+	switch args.Key {
+	case object.NameKey, object.EmailKey:
+	default:
+		return errors.New("invalid setting")
+	}
+
+	key, err := p2p.DecodeKey(s.Config.PrivateKey)
+	if err != nil {
+		return err
+	}
+
+	author := s.Config.Author
+	if author.Metadata == nil {
+		author.Metadata = make(map[string]string)
+	}
+
+	author.Metadata[args.Key] = args.Value
+	if err := s.Config.Write(); err != nil {
+		return err
+	}
+
+	authorID, err := object.AddAuthor(ctx, s.Peer.DAG, author)
+	if err != nil {
+		return err
+	}
+
+	return s.Namesys.Publish(ctx, key, authorID)
+}
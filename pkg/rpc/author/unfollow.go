@@ -15,6 +15,8 @@ type UnfollowReply struct{}
 
 // Unfollow returns the author for the given peer ID.
 func (s *Service) Unfollow(args *UnfollowArgs, reply *UnfollowReply) error {
+	defer s.Peer.Blocks.PinLock().Unlock()
+
 	if err := args.PeerID.Validate(); err != nil {
 		return err
 	}
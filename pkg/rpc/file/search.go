@@ -3,11 +3,9 @@ package file
 import (
 	"context"
 	"errors"
-	"strings"
 
 	path "github.com/ipfs/go-path"
 	unixfs "github.com/ipfs/go-unixfs"
-	"github.com/libp2p/go-libp2p-core/peer"
 	"github.com/multiverse-vcs/go-multiverse/pkg/fs"
 	"github.com/multiverse-vcs/go-multiverse/pkg/object"
 )
@@ -37,15 +35,7 @@ func (s *Service) Search(args *SearchArgs, reply *SearchReply) error {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	parts := strings.Split(args.Remote, "/")
-	if len(parts) != 2 {
-		return errors.New("invalid remote path")
-	}
-
-	pname := parts[0]
-	rname := parts[1]
-
-	peerID, err := peer.Decode(pname)
+	peerID, rname, err := s.Config.ResolvePath(ctx, args.Remote)
 	if err != nil {
 		return err
 	}
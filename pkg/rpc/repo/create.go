@@ -25,6 +25,7 @@ type CreateReply struct {
 func (s *Service) Create(args *CreateArgs, reply *CreateReply) error {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
+	defer s.Peer.Blocks.PinLock().Unlock()
 
 	if args.Name == "" {
 		return errors.New("name cannot be empty")
@@ -21,6 +21,7 @@ type DeleteReply struct{}
 func (s *Service) Delete(args *DeleteArgs, reply *DeleteReply) error {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
+	defer s.Peer.Blocks.PinLock().Unlock()
 
 	key, err := p2p.DecodeKey(s.Config.PrivateKey)
 	if err != nil {
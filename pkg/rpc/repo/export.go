@@ -0,0 +1,57 @@
+package repo
+
+import (
+	"context"
+	"errors"
+
+	"github.com/multiverse-vcs/go-multiverse/internal/git"
+	"github.com/multiverse-vcs/go-multiverse/pkg/object"
+)
+
+// ExportArgs contains the args.
+type ExportArgs struct {
+	// Remote is the remote path.
+	Remote string `json:"remote"`
+	// Path is the git repository directory.
+	Path string `json:"path"`
+}
+
+// ExportReply contains the reply.
+type ExportReply struct{}
+
+// Export writes the repository at the remote path to a git repository.
+func (s *Service) Export(args *ExportArgs, reply *ExportReply) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if args.Path == "" {
+		return errors.New("export path must be set")
+	}
+
+	peerID, rname, err := s.Config.ResolvePath(ctx, args.Remote)
+	if err != nil {
+		return err
+	}
+
+	authorID, err := s.Namesys.Search(ctx, peerID)
+	if err != nil {
+		return err
+	}
+
+	author, err := object.GetAuthor(ctx, s.Peer.DAG, authorID)
+	if err != nil {
+		return err
+	}
+
+	repoID, ok := author.Repositories[rname]
+	if !ok {
+		return errors.New("repository does not exist")
+	}
+
+	repo, err := object.GetRepository(ctx, s.Peer.DAG, repoID)
+	if err != nil {
+		return err
+	}
+
+	return git.ExportToFS(ctx, s.Peer.DAG, repo, args.Path)
+}
@@ -0,0 +1,32 @@
+package repo
+
+import (
+	"context"
+
+	cid "github.com/ipfs/go-cid"
+)
+
+// FetchArgs contains the args.
+type FetchArgs struct {
+	// Remote is the remote path.
+	Remote string
+	// Want contains the roots of the dags to fetch.
+	Want []cid.Cid
+}
+
+// FetchReply contains the reply.
+type FetchReply struct{}
+
+// Fetch adds the dags of a remote repository from its author so they can be synced by clients.
+func (s *Service) Fetch(args *FetchArgs, reply *FetchReply) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	defer s.Peer.Blocks.PinLock().Unlock()
+
+	peerID, _, err := s.Config.ResolvePath(ctx, args.Remote)
+	if err != nil {
+		return err
+	}
+
+	return s.fetchRemote(ctx, peerID, args.Want)
+}
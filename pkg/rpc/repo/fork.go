@@ -3,11 +3,9 @@ package repo
 import (
 	"context"
 	"errors"
-	"strings"
 	"path"
 
 	merkledag "github.com/ipfs/go-merkledag"
-	"github.com/libp2p/go-libp2p-core/peer"
 
 	"github.com/multiverse-vcs/go-multiverse/internal/p2p"
 	"github.com/multiverse-vcs/go-multiverse/pkg/object"
@@ -31,15 +29,13 @@ type ForkReply struct {
 func (s *Service) Fork(args *ForkArgs, reply *ForkReply) error {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
+	defer s.Peer.Blocks.PinLock().Unlock()
 
-	parts := strings.Split(args.Remote, "/")
-	if len(parts) != 2 {
-		return errors.New("invalid remote path")
+	peerID, rname, err := s.Config.ResolvePath(ctx, args.Remote)
+	if err != nil {
+		return err
 	}
 
-	pname := parts[0]
-	rname := parts[1]
-
 	author := s.Config.Author
 	rename := rname
 
@@ -48,11 +44,6 @@ func (s *Service) Fork(args *ForkArgs, reply *ForkReply) error {
 		return err
 	}
 
-	peerID, err := peer.Decode(pname)
-	if err != nil {
-		return err
-	}
-
 	sourceID, err := s.Namesys.Search(ctx, peerID)
 	if err != nil {
 		return err
@@ -0,0 +1,45 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/multiverse-vcs/go-multiverse/pkg/dag"
+)
+
+// FsckArgs contains the args.
+type FsckArgs struct{}
+
+// FsckReply contains the reply.
+type FsckReply struct {
+	// Count is the number of objects checked.
+	Count int `json:"count"`
+	// Problems contains damaged or missing objects.
+	Problems []*dag.Problem `json:"problems"`
+}
+
+// Fsck verifies the integrity of all published and pinned repositories.
+func (s *Service) Fsck(args *FsckArgs, reply *FsckReply) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	defer s.Peer.Blocks.PinLock().Unlock()
+
+	checker := dag.NewChecker(s.Peer.Blocks)
+	for name, id := range s.Config.Author.Repositories {
+		ref := fmt.Sprintf("repository %s", name)
+		if err := checker.Check(ctx, ref, dag.RepositoryObject, id); err != nil {
+			return err
+		}
+	}
+
+	for pin, id := range s.Config.Pins {
+		ref := fmt.Sprintf("pin %s", pin)
+		if err := checker.Check(ctx, ref, dag.RepositoryObject, id); err != nil {
+			return err
+		}
+	}
+
+	reply.Count = checker.Count
+	reply.Problems = checker.Problems
+	return nil
+}
@@ -20,6 +20,10 @@ type ImportArgs struct {
 	URL string `json:"url"`
 	// Path is the repository directory.
 	Path string `json:"path"`
+	// Update imports new commits into an existing repository.
+	Update bool `json:"update"`
+	// Force overwrites branches that are not fast-forwards.
+	Force bool `json:"force"`
 }
 
 // ImportReply contains the reply
@@ -32,6 +36,7 @@ type ImportReply struct {
 func (s *Service) Import(args *ImportArgs, reply *ImportReply) error {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
+	defer s.Peer.Blocks.PinLock().Unlock()
 
 	if args.Name == "" {
 		return errors.New("name cannot be empty")
@@ -43,19 +48,37 @@ func (s *Service) Import(args *ImportArgs, reply *ImportReply) error {
 	}
 
 	author := s.Config.Author
-	if _, ok := author.Repositories[args.Name]; ok {
+	prevID, ok := author.Repositories[args.Name]
+	if ok && !args.Update {
 		return errors.New("repository already exists")
 	}
 
+	if !ok && args.Update {
+		return errors.New("repository does not exist")
+	}
+
+	var prev *object.Repository
+	if args.Update {
+		prev, err = object.GetRepository(ctx, s.Peer.DAG, prevID)
+		if err != nil {
+			return err
+		}
+	}
+
+	url, dir := args.URL, args.Path
+	if prev != nil && url == "" && dir == "" {
+		url, dir = prev.Metadata[git.URLKey], prev.Metadata[git.PathKey]
+	}
+
 	var repoID cid.Cid
 	switch {
-	case args.URL != "":
-		repoID, err = git.ImportFromURL(ctx, s.Peer.DAG, args.Name, args.URL)
+	case url != "":
+		repoID, err = git.UpdateFromURL(ctx, s.Peer.DAG, prev, args.Name, url, args.Force)
 		if err != nil {
 			return err
 		}
-	case args.Path != "":
-		repoID, err = git.ImportFromFS(ctx, s.Peer.DAG, args.Name, args.Path)
+	case dir != "":
+		repoID, err = git.UpdateFromFS(ctx, s.Peer.DAG, prev, args.Name, dir, args.Force)
 		if err != nil {
 			return err
 		}
@@ -0,0 +1,41 @@
+package repo
+
+import (
+	"context"
+
+	cid "github.com/ipfs/go-cid"
+)
+
+// PinArgs contains the args.
+type PinArgs struct {
+	// Remote is the remote path.
+	Remote string `json:"remote"`
+}
+
+// PinReply contains the reply.
+type PinReply struct {
+	// Remote is the resolved remote path.
+	Remote string `json:"remote"`
+	// Pins maps remote paths to pinned repository CIDs.
+	Pins map[string]cid.Cid `json:"pins"`
+}
+
+// Pin keeps the repository at the remote path and its history on the daemon.
+//
+// The current pins are returned when no remote is given.
+func (s *Service) Pin(args *PinArgs, reply *PinReply) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if args.Remote != "" {
+		remote, err := s.PinRepository(ctx, args.Remote)
+		if err != nil {
+			return err
+		}
+
+		reply.Remote = remote
+	}
+
+	reply.Pins = s.Config.Pins
+	return nil
+}
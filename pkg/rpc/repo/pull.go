@@ -1,15 +1,11 @@
 package repo
 
 import (
-	"bytes"
 	"context"
 	"errors"
-	"strings"
 
 	cid "github.com/ipfs/go-cid"
-	"github.com/libp2p/go-libp2p-core/peer"
 
-	"github.com/multiverse-vcs/go-multiverse/pkg/dag"
 	"github.com/multiverse-vcs/go-multiverse/pkg/object"
 )
 
@@ -19,29 +15,21 @@ type PullArgs struct {
 	Remote string
 	// Branch is the branch name.
 	Branch string
-	// Refs is a list of known references.
-	Refs []cid.Cid
 }
 
 // PullReply contains the reply.
 type PullReply struct {
-	// Data contains objects to add.
-	Data []byte
+	// Head is the branch head to fetch with the sync protocol.
+	Head cid.Cid
 }
 
+// Pull returns the head of the remote branch after fetching its objects from the author.
 func (s *Service) Pull(args *PullArgs, reply *PullReply) error {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
+	defer s.Peer.Blocks.PinLock().Unlock()
 
-	parts := strings.Split(args.Remote, "/")
-	if len(parts) != 2 {
-		return errors.New("invalid remote")
-	}
-
-	pname := parts[0]
-	rname := parts[1]
-
-	peerID, err := peer.Decode(pname)
+	peerID, rname, err := s.Config.ResolvePath(ctx, args.Remote)
 	if err != nil {
 		return err
 	}
@@ -71,16 +59,10 @@ func (s *Service) Pull(args *PullArgs, reply *PullReply) error {
 		return errors.New("branch does not exist")
 	}
 
-	refs := cid.NewSet()
-	for _, id := range args.Refs {
-		refs.Add(id)
-	}
-
-	var data bytes.Buffer
-	if err := dag.WriteCar(ctx, s.Peer.DAG, head, refs, &data); err != nil {
+	if err := s.fetchRemote(ctx, peerID, []cid.Cid{head}); err != nil {
 		return err
 	}
 
-	reply.Data = data.Bytes()
+	reply.Head = head
 	return nil
 }
@@ -0,0 +1,100 @@
+package repo
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	cid "github.com/ipfs/go-cid"
+	ipld "github.com/ipfs/go-ipld-format"
+	merkledag "github.com/ipfs/go-merkledag"
+	ufsio "github.com/ipfs/go-unixfs/io"
+
+	"github.com/multiverse-vcs/go-multiverse/internal/remotetest"
+	"github.com/multiverse-vcs/go-multiverse/pkg/object"
+)
+
+// addCommit adds a commit with a single file to the dag.
+func addCommit(t *testing.T, ctx context.Context, dag ipld.DAGService, content string, parents ...cid.Cid) cid.Cid {
+	file := merkledag.NewRawNode([]byte(content))
+	if err := dag.Add(ctx, file); err != nil {
+		t.Fatal("failed to add file")
+	}
+
+	dir := ufsio.NewDirectory(dag)
+	if err := dir.AddChild(ctx, "README", file); err != nil {
+		t.Fatal("failed to add child")
+	}
+
+	tree, err := dir.GetNode()
+	if err != nil {
+		t.Fatal("failed to get tree")
+	}
+
+	if err := dag.Add(ctx, tree); err != nil {
+		t.Fatal("failed to add tree")
+	}
+
+	commit := object.NewCommit()
+	commit.Tree = tree.Cid()
+	commit.Message = content
+	commit.Parents = parents
+	commit.Date = time.Now()
+
+	id, err := object.AddCommit(ctx, dag, commit)
+	if err != nil {
+		t.Fatal("failed to add commit")
+	}
+
+	return id
+}
+
+func TestPullRemote(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	dir, err := ioutil.TempDir("", "multi-*")
+	if err != nil {
+		t.Fatal("failed to create temp dir")
+	}
+	defer os.RemoveAll(dir)
+
+	_, servers, err := remotetest.NewNetwork(ctx, dir, 2)
+	if err != nil {
+		t.Fatalf("failed to create network %v", err)
+	}
+
+	local, owner := servers[0], servers[1]
+
+	first := addCommit(t, ctx, owner.Peer.Local, "first")
+	second := addCommit(t, ctx, owner.Peer.Local, "second", first)
+
+	repo := object.NewRepository()
+	repo.Branches["default"] = second
+
+	if err := remotetest.AddRepository(ctx, owner, "test", repo); err != nil {
+		t.Fatalf("failed to add repository %v", err)
+	}
+
+	args := PullArgs{
+		Remote: path.Join(owner.Peer.Host.ID().Pretty(), "test"),
+		Branch: "default",
+	}
+
+	var reply PullReply
+	if err := (&Service{local}).Pull(&args, &reply); err != nil {
+		t.Fatalf("failed to pull %v", err)
+	}
+
+	if reply.Head != second {
+		t.Error("unexpected branch head")
+	}
+
+	// the whole branch must be available without the network
+	if err := merkledag.FetchGraph(ctx, second, local.Peer.Local); err != nil {
+		t.Errorf("expected branch to be fetched %v", err)
+	}
+}
@@ -1,15 +1,14 @@
 package repo
 
 import (
-	"bytes"
 	"context"
 	"errors"
-	"strings"
 
+	cid "github.com/ipfs/go-cid"
+	merkledag "github.com/ipfs/go-merkledag"
 	"github.com/libp2p/go-libp2p-core/peer"
 
 	"github.com/multiverse-vcs/go-multiverse/internal/p2p"
-	"github.com/multiverse-vcs/go-multiverse/pkg/dag"
 	"github.com/multiverse-vcs/go-multiverse/pkg/merge"
 	"github.com/multiverse-vcs/go-multiverse/pkg/object"
 )
@@ -20,8 +19,10 @@ type PushArgs struct {
 	Remote string
 	// branch is the branch name.
 	Branch string
-	// Data contains objects to add.
-	Data []byte
+	// Head is the new branch head.
+	Head cid.Cid
+	// PeerID is the peer to fetch objects from with the sync protocol.
+	PeerID peer.ID
 }
 
 // PushReply contains the reply.
@@ -31,20 +32,41 @@ func (s *Service) Push(args *PushArgs, reply *PushReply) error {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	parts := strings.Split(args.Remote, "/")
-	if len(parts) != 2 {
-		return errors.New("invalid remote")
+	update := func(repo *object.Repository) error {
+		prev := repo.Branches[args.Branch]
+		next := args.Head
+
+		if err := s.fetch(ctx, args.PeerID, next, repo); err != nil {
+			return err
+		}
+
+		base, err := merge.Base(ctx, s.Peer.DAG, prev, next)
+		if err != nil {
+			return err
+		}
+
+		if base != prev {
+			return errors.New("branches are non-divergent")
+		}
+
+		repo.Branches[args.Branch] = next
+		return nil
 	}
 
-	pname := parts[0]
-	rname := parts[1]
+	return s.updateRepository(ctx, args.Remote, update)
+}
 
-	key, err := p2p.DecodeKey(s.Config.PrivateKey)
+// updateRepository applies the update to the local repository at remote and publishes the result.
+func (s *Service) updateRepository(ctx context.Context, remote string, update func(*object.Repository) error) error {
+	// hold the pin lock until the config references the new objects
+	defer s.Peer.Blocks.PinLock().Unlock()
+
+	peerID, rname, err := s.Config.ResolvePath(ctx, remote)
 	if err != nil {
 		return err
 	}
 
-	peerID, err := peer.Decode(pname)
+	key, err := p2p.DecodeKey(s.Config.PrivateKey)
 	if err != nil {
 		return err
 	}
@@ -64,22 +86,10 @@ func (s *Service) Push(args *PushArgs, reply *PushReply) error {
 		return err
 	}
 
-	prev := repo.Branches[args.Branch]
-	next, err := dag.ReadCar(s.Peer.Blocks, bytes.NewReader(args.Data))
-	if err != nil {
-		return err
-	}
-
-	base, err := merge.Base(ctx, s.Peer.DAG, prev, next)
-	if err != nil {
+	if err := update(repo); err != nil {
 		return err
 	}
 
-	if base != prev {
-		return errors.New("branches are non-divergent")
-	}
-
-	repo.Branches[args.Branch] = next
 	repoID, err = object.AddRepository(ctx, s.Peer.DAG, repo)
 	if err != nil {
 		return err
@@ -97,3 +107,32 @@ func (s *Service) Push(args *PushArgs, reply *PushReply) error {
 
 	return s.Namesys.Publish(ctx, key, authorID)
 }
+
+// fetch adds the dag at id from the peer, skipping objects already in the repository.
+func (s *Service) fetch(ctx context.Context, peerID peer.ID, id cid.Cid, repo *object.Repository) error {
+	have := repo.Heads()
+	for _, tag := range repo.Tags {
+		have.Add(tag)
+	}
+
+	want := []cid.Cid{id}
+	return p2p.Fetch(ctx, s.Peer.Host, peerID, s.Peer.Blocks, want, have.Keys())
+}
+
+// fetchRemote adds the dags at want from the peer that owns them.
+//
+// Dags that are already stored locally are not fetched again.
+func (s *Service) fetchRemote(ctx context.Context, peerID peer.ID, want []cid.Cid) error {
+	var missing []cid.Cid
+	for _, id := range want {
+		if err := merkledag.FetchGraph(ctx, id, s.Peer.Local); err != nil {
+			missing = append(missing, id)
+		}
+	}
+
+	if len(missing) == 0 {
+		return nil
+	}
+
+	return p2p.Fetch(ctx, s.Peer.Host, peerID, s.Peer.Blocks, missing, nil)
+}
@@ -3,9 +3,6 @@ package repo
 import (
 	"context"
 	"errors"
-	"strings"
-
-	"github.com/libp2p/go-libp2p-core/peer"
 
 	"github.com/multiverse-vcs/go-multiverse/pkg/object"
 )
@@ -27,15 +24,7 @@ func (s *Service) Search(args *SearchArgs, reply *SearchReply) error {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	parts := strings.Split(args.Remote, "/")
-	if len(parts) != 2 {
-		return errors.New("invalid remote path")
-	}
-
-	pname := parts[0]
-	rname := parts[1]
-
-	peerID, err := peer.Decode(pname)
+	peerID, rname, err := s.Config.ResolvePath(ctx, args.Remote)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,57 @@
+package repo
+
+import (
+	"context"
+	"errors"
+
+	cid "github.com/ipfs/go-cid"
+	"github.com/libp2p/go-libp2p-core/peer"
+
+	"github.com/multiverse-vcs/go-multiverse/pkg/object"
+)
+
+// TagArgs contains the args.
+type TagArgs struct {
+	// Remote is the remote path.
+	Remote string
+	// Tag is the tag name.
+	Tag string
+	// Force allows replacing an existing tag.
+	Force bool
+	// ID is the tag object or commit id.
+	ID cid.Cid
+	// PeerID is the peer to fetch objects from with the sync protocol.
+	PeerID peer.ID
+}
+
+// TagReply contains the reply.
+type TagReply struct{}
+
+// Tag updates a remote tag with the tag object fetched from the peer.
+func (s *Service) Tag(args *TagArgs, reply *TagReply) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	update := func(repo *object.Repository) error {
+		id := args.ID
+		if err := s.fetch(ctx, args.PeerID, id, repo); err != nil {
+			return err
+		}
+
+		// lightweight tags reference commits directly
+		if _, err := object.GetTag(ctx, s.Peer.DAG, id); err != nil {
+			if _, cerr := object.GetCommit(ctx, s.Peer.DAG, id); cerr != nil {
+				return err
+			}
+		}
+
+		if prev, ok := repo.Tags[args.Tag]; ok && prev != id && !args.Force {
+			return errors.New("tag already exists")
+		}
+
+		repo.Tags[args.Tag] = id
+		return nil
+	}
+
+	return s.updateRepository(ctx, args.Remote, update)
+}
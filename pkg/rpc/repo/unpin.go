@@ -0,0 +1,31 @@
+package repo
+
+import (
+	"context"
+)
+
+// UnpinArgs contains the args.
+type UnpinArgs struct {
+	// Remote is the remote path.
+	Remote string `json:"remote"`
+}
+
+// UnpinReply contains the reply.
+type UnpinReply struct {
+	// Remote is the resolved remote path.
+	Remote string `json:"remote"`
+}
+
+// Unpin allows the repository at the remote path to be garbage collected.
+func (s *Service) Unpin(args *UnpinArgs, reply *UnpinReply) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	remote, err := s.UnpinRepository(ctx, args.Remote)
+	if err != nil {
+		return err
+	}
+
+	reply.Remote = remote
+	return nil
+}